@@ -1,6 +1,11 @@
 package config
 
-import "os"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+)
 
 // Only change these values when using this package as a library.
 
@@ -14,6 +19,24 @@ const OpenAPIPath = ""
 // This will default to AEP_PATH_PREFIX if empty.
 const PathPrefix = ""
 
+// A directory used to cache OpenAPI specs fetched over HTTP(S).
+// Cached specs are revalidated with ETag / If-Modified-Since on every start,
+// and are used as-is if the spec host can't be reached.
+// This will default to AEP_OPENAPI_CACHE_DIR if empty. Caching is disabled if both are empty.
+const OpenAPICacheDir = ""
+
+// The number of times a recursive schema, such as a tree node that contains
+// nodes of its own type, is expanded inside itself. Past this depth, the
+// recursive field is a JSON string.
+// This will default to AEP_SCHEMA_RECURSION_DEPTH if 0, and then to 1.
+const SchemaRecursionDepth = 0
+
+// Generate free-form values, such as objects without properties and
+// google.protobuf.Value fields, as dynamic attributes that take any HCL value,
+// instead of JSON strings.
+// This will default to AEP_DYNAMIC_VALUES if false.
+const DynamicValues = false
+
 // The name of your provider.
 // All resources will have the prefix `prefix_resource`.
 const ProviderPrefix = "aep"
@@ -24,9 +47,25 @@ const RegistryURL = "registry.terraform.io/aep-dev/aep"
 
 // Do not change anything below here!
 
+// OpenAPISpec is one of the OpenAPI specs whose resources make up the provider.
+type OpenAPISpec struct {
+	// The URI where the OpenAPI spec lives.
+	Path string `json:"path"`
+	// The path prefix of every method in the spec. See PathPrefix.
+	PathPrefix string `json:"path_prefix,omitempty"`
+	// The URL of the server the resources are managed on.
+	// This will default to the server in the spec if empty.
+	ServerURL string `json:"server_url,omitempty"`
+}
+
 type ProviderConfig struct {
-	openAPIPath string
-	pathPrefix  string
+	openAPIPath     string
+	pathPrefix      string
+	openAPICacheDir string
+	openAPISpecs    []OpenAPISpec
+
+	schemaRecursionDepth int
+	dynamicValues        bool
 
 	ProviderPrefix string
 	RegistryURL    string
@@ -46,12 +85,106 @@ func (c *ProviderConfig) PathPrefix() string {
 	return os.Getenv("AEP_PATH_PREFIX")
 }
 
+func (c *ProviderConfig) OpenAPICacheDir() string {
+	if c.openAPICacheDir != "" {
+		return c.openAPICacheDir
+	}
+	return os.Getenv("AEP_OPENAPI_CACHE_DIR")
+}
+
+// OpenAPISpecs returns the specs whose resources are merged into the provider.
+// This will default to the JSON list in AEP_OPENAPI_SPECS, and then to the
+// single spec at OpenAPIPath with PathPrefix.
+func (c *ProviderConfig) OpenAPISpecs() ([]OpenAPISpec, error) {
+	if len(c.openAPISpecs) > 0 {
+		return c.openAPISpecs, nil
+	}
+	if v := os.Getenv("AEP_OPENAPI_SPECS"); v != "" {
+		var specs []OpenAPISpec
+		if err := json.Unmarshal([]byte(v), &specs); err != nil {
+			return nil, fmt.Errorf("AEP_OPENAPI_SPECS must be a JSON list of specs: %w", err)
+		}
+		for i, spec := range specs {
+			if spec.Path == "" {
+				return nil, fmt.Errorf("AEP_OPENAPI_SPECS: spec %d has no path", i)
+			}
+		}
+		return specs, nil
+	}
+	return []OpenAPISpec{{Path: c.OpenAPIPath(), PathPrefix: c.PathPrefix()}}, nil
+}
+
+// SchemaRecursionDepth returns the number of times a recursive schema is
+// expanded inside itself. See SchemaRecursionDepth.
+func (c *ProviderConfig) SchemaRecursionDepth() (int, error) {
+	if c.schemaRecursionDepth != 0 {
+		return c.schemaRecursionDepth, nil
+	}
+	if v := os.Getenv("AEP_SCHEMA_RECURSION_DEPTH"); v != "" {
+		depth, err := strconv.Atoi(v)
+		if err != nil || depth < 0 {
+			return 0, fmt.Errorf("AEP_SCHEMA_RECURSION_DEPTH must be a non-negative integer, got %q", v)
+		}
+		return depth, nil
+	}
+	return 1, nil
+}
+
+// DynamicValues reports whether free-form values are dynamic attributes. See
+// DynamicValues.
+func (c *ProviderConfig) DynamicValues() (bool, error) {
+	if c.dynamicValues {
+		return true, nil
+	}
+	if v := os.Getenv("AEP_DYNAMIC_VALUES"); v != "" {
+		dynamic, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("AEP_DYNAMIC_VALUES must be a boolean, got %q", v)
+		}
+		return dynamic, nil
+	}
+	return false, nil
+}
+
+// GeneratorOptions are the settings the resource schemas are generated with.
+type GeneratorOptions struct {
+	// The directory specs fetched over HTTP(S) are cached in. See
+	// OpenAPICacheDir.
+	CacheDir string
+	// The number of times a recursive schema is expanded inside itself. See
+	// SchemaRecursionDepth.
+	RecursionDepth int
+	// Whether free-form values are dynamic attributes. See DynamicValues.
+	DynamicValues bool
+}
+
+// GeneratorOptions returns the settings the resource schemas are generated
+// with.
+func (c *ProviderConfig) GeneratorOptions() (GeneratorOptions, error) {
+	recursionDepth, err := c.SchemaRecursionDepth()
+	if err != nil {
+		return GeneratorOptions{}, err
+	}
+	dynamicValues, err := c.DynamicValues()
+	if err != nil {
+		return GeneratorOptions{}, err
+	}
+	return GeneratorOptions{
+		CacheDir:       c.OpenAPICacheDir(),
+		RecursionDepth: recursionDepth,
+		DynamicValues:  dynamicValues,
+	}, nil
+}
+
 func NewProviderConfig() ProviderConfig {
 	return ProviderConfig{
-		openAPIPath:    OpenAPIPath,
-		pathPrefix:     PathPrefix,
-		RegistryURL:    RegistryURL,
-		ProviderPrefix: ProviderPrefix,
+		openAPIPath:          OpenAPIPath,
+		pathPrefix:           PathPrefix,
+		openAPICacheDir:      OpenAPICacheDir,
+		schemaRecursionDepth: SchemaRecursionDepth,
+		dynamicValues:        DynamicValues,
+		RegistryURL:          RegistryURL,
+		ProviderPrefix:       ProviderPrefix,
 	}
 }
 
@@ -65,3 +198,14 @@ func NewProviderConfigWithOptions(openAPI string, pathPrefix string, registryUrl
 		ProviderPrefix: providerPrefix,
 	}
 }
+
+// NewProviderConfigWithSpecs creates a ProviderConfig that merges the resources
+// of several OpenAPI specs into one provider.
+// Use this when embedding the provider as a library.
+func NewProviderConfigWithSpecs(specs []OpenAPISpec, registryUrl string, providerPrefix string) ProviderConfig {
+	return ProviderConfig{
+		openAPISpecs:   specs,
+		RegistryURL:    registryUrl,
+		ProviderPrefix: providerPrefix,
+	}
+}
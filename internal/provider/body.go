@@ -7,71 +7,52 @@ import (
 
 	"github.com/aep-dev/aep-lib-go/pkg/api"
 	"github.com/aep-dev/terraform-provider-aep/internal/provider/data"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Returns the proper formatted body for Create / Update requests.
 func Body(ctx context.Context, d *data.Resource, r *data.ResourceSchema) (map[string]interface{}, error) {
-	jsonDataMap, err := d.ToJSON()
-	if err != nil {
-		return nil, err
-	}
-
-	attributes := r.SchemaAttributes()
-
+	// The values are converted one by one, since a field renamed away from a
+	// parameter still has the parameter's name in JSON.
 	result := make(map[string]interface{})
-	for key, value := range jsonDataMap {
-		if _, ok := attributes[key]; ok {
-			result[key] = value
+	for name, jsonName := range r.JSONNames() {
+		v, ok := d.Values[name]
+		if !ok {
+			continue
+		}
+		converted, err := data.ConvertValue(v, r.Attributes[name])
+		if err != nil {
+			return nil, err
 		}
+		result[jsonName] = converted
 	}
 	return result, nil
 }
 
 // Returns a map that can be used to substitute parent values into a URI.
 func Parameters(ctx context.Context, d *data.Resource, r *data.ResourceSchema) (map[string]string, error) {
-	jsonData, err := d.ToJSON()
-	if err != nil {
-		return nil, err
-	}
-
-	parameterAttributes := r.Parameters()
-
-	tflog.Info(ctx, fmt.Sprintf("plan data json: %q", jsonData))
 	result := make(map[string]string)
-	for key, value := range jsonData {
-		tflog.Info(ctx, fmt.Sprintf("key %s", key))
-		if _, ok := parameterAttributes[key]; ok {
-			strValue, ok := value.(string)
-			if !ok {
-				return nil, fmt.Errorf("value %v for key %s is not a string", value, key)
-			}
-			result[key] = strValue
+	for key := range r.Parameters() {
+		v, ok := d.Values[key]
+		if !ok {
+			continue
+		}
+		if v.String == nil {
+			return nil, fmt.Errorf("value %v for key %s is not a string", v, key)
 		}
+		result[key] = *v.String
 	}
 	return result, nil
 }
 
 // Create state from the API response and plan.
 func State(ctx context.Context, resp map[string]interface{}, plan *data.Resource, r *data.ResourceSchema) (*data.Resource, error) {
-	s := r.SchemaAttributes()
-
-	p := r.Parameters()
-
+	// result is keyed by JSON names, as the response is. Parameters aren't
+	// returned by the API, so they keep their planned values.
 	result := make(map[string]interface{})
-	for k := range s {
-		v, ok := resp[k]
+	for _, jsonName := range r.JSONNames() {
+		v, ok := resp[jsonName]
 		if ok {
-			result[k] = v
-		}
-	}
-
-	// Add parameters back into state.
-	// These aren't returned by the API.
-	for k := range p {
-		v, ok := plan.Values[k]
-		if ok && v.String != nil {
-			result[k] = *v.String
+			result[jsonName] = v
 		}
 	}
 
@@ -101,6 +82,12 @@ func State(ctx context.Context, resp map[string]interface{}, plan *data.Resource
 	if err != nil {
 		return nil, err
 	}
+
+	// Record the field numbers of the current spec, so that the state can be
+	// upgraded when fields are renamed.
+	if _, ok := r.Attributes[data.FieldNumbersAttribute]; ok {
+		plan.Values[data.FieldNumbersAttribute] = r.FieldNumbersValue()
+	}
 	return plan, nil
 }
 
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/aep-dev/aep-lib-go/pkg/api"
+	"github.com/aep-dev/aep-lib-go/pkg/openapi"
+	"github.com/aep-dev/terraform-provider-aep/internal/provider/data"
+)
+
+const bodyTestSpec = `{
+  "components": {
+    "schemas": {
+      "book": {
+        "x-aep-resource": {"singular": "book"},
+        "properties": {
+          "count": {"type": "integer"},
+          "displayName": {"type": "string"},
+          "publisher": {"type": "string"},
+          "path": {"type": "string", "readOnly": true}
+        }
+      }
+    }
+  }
+}`
+
+func TestBodyAndState(t *testing.T) {
+	spec, err := data.ParseSpec([]byte(bodyTestSpec))
+	if err != nil {
+		t.Fatalf("ParseSpec() error = %v", err)
+	}
+	r := &api.Resource{
+		Singular:     "book",
+		Schema:       &openapi.Schema{},
+		PatternElems: []string{"publishers", "{publisher}", "books", "{book}"},
+		CreateMethod: &api.CreateMethod{},
+	}
+	s, err := data.NewResourceSchemaFromSpec(context.Background(), r, spec)
+	if err != nil {
+		t.Fatalf("NewResourceSchemaFromSpec() error = %v", err)
+	}
+
+	// count is reserved by Terraform, displayName isn't snake case and the
+	// publisher field gives way to the publisher parameter, so none of them
+	// keeps its JSON name.
+	wantNames := map[string]string{"count_": "count", "display_name": "displayName", "publisher_2": "publisher"}
+	for name, jsonName := range wantNames {
+		if got := s.JSONNames()[name]; got != jsonName {
+			t.Fatalf("JSONNames()[%q] = %q, want %q", name, got, jsonName)
+		}
+	}
+
+	plan := &data.Resource{
+		Schema: s,
+		Values: map[string]data.Value{
+			"count_":       {Number: big.NewFloat(3)},
+			"display_name": {String: data.String("Example")},
+			"publisher":    {String: data.String("acme")},
+			"publisher_2":  {String: data.String("Acme Books")},
+		},
+	}
+
+	body, err := Body(context.Background(), plan, s)
+	if err != nil {
+		t.Fatalf("Body() error = %v", err)
+	}
+	wantBody := map[string]interface{}{"count": json.Number("3"), "displayName": "Example", "publisher": "Acme Books"}
+	if !reflect.DeepEqual(body, wantBody) {
+		t.Errorf("Body() = %v, want %v", body, wantBody)
+	}
+
+	params, err := Parameters(context.Background(), plan, s)
+	if err != nil {
+		t.Fatalf("Parameters() error = %v", err)
+	}
+	if want := map[string]string{"publisher": "acme"}; !reflect.DeepEqual(params, want) {
+		t.Errorf("Parameters() = %v, want %v", params, want)
+	}
+
+	resp := map[string]interface{}{
+		"path":        "publishers/acme/books/1",
+		"count":       json.Number("4"),
+		"displayName": "Updated",
+		"publisher":   "Acme Publishing",
+	}
+	state, err := State(context.Background(), resp, plan, s)
+	if err != nil {
+		t.Fatalf("State() error = %v", err)
+	}
+	if v := state.Values["count_"]; v.Number == nil || v.Number.Cmp(big.NewFloat(4)) != 0 {
+		t.Errorf("State() count_ = %v, want 4", v.Number)
+	}
+	if v := state.Values["display_name"]; v.String == nil || *v.String != "Updated" {
+		t.Errorf("State() display_name = %v, want Updated", v.String)
+	}
+	// The field and the parameter with the same JSON name keep their own
+	// values.
+	if v := state.Values["publisher"]; v.String == nil || *v.String != "acme" {
+		t.Errorf("State() publisher = %v, want acme", v.String)
+	}
+	if v := state.Values["publisher_2"]; v.String == nil || *v.String != "Acme Publishing" {
+		t.Errorf("State() publisher_2 = %v, want Acme Publishing", v.String)
+	}
+}
@@ -48,8 +48,9 @@ type CollectionDataSource struct {
 	api      *api.API
 	name     string
 
-	// Client will be configured at plan/apply time in the Configure() function.
+	// Client and endpoint will be configured at plan/apply time in the Configure() function.
 	client         *client.Client
+	endpoint       string
 	o              *openapi.OpenAPI
 	resourceSchema *data.ResourceSchema
 }
@@ -62,7 +63,8 @@ func (d *CollectionDataSource) Schema(ctx context.Context, req datasource.Schema
 	attr := d.resourceSchema.FullCollectionDataSourceSchema(ctx)
 
 	resp.Schema = schema.Schema{
-		MarkdownDescription: d.resource.Singular,
+		MarkdownDescription: d.resourceSchema.Description,
+		DeprecationMessage:  d.resourceSchema.DeprecationMessage,
 
 		Attributes: attr,
 	}
@@ -74,18 +76,28 @@ func (d *CollectionDataSource) Configure(ctx context.Context, req datasource.Con
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.Client)
+	providerData, ok := req.ProviderData.(*ConfiguredProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *ConfiguredProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client
+	d.client = providerData.client
+	d.endpoint = providerData.endpoint
+}
+
+// serverURL returns the URL requests are sent to.
+// The provider's endpoint takes precedence over the server URL in the spec.
+func (d *CollectionDataSource) serverURL() string {
+	if d.endpoint != "" {
+		return d.endpoint
+	}
+	return d.api.ServerURL
 }
 
 func (d *CollectionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -109,11 +121,13 @@ func (d *CollectionDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		return
 	}
 
-	a, err := d.client.List(ctx, d.resource, d.api.ServerURL, parameters)
+	listCtx, responses := withResponses(ctx)
+	a, err := d.client.List(listCtx, d.resource, d.serverURL(), parameters)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read example, got error: %s", err))
 		return
 	}
+	a = responses.list(a)
 
 	dataState, err := DataSourceState(ctx, a, dataResource, d.resource)
 	if err != nil {
@@ -0,0 +1,128 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/helpers/validatordiag"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	tfschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// oneOfAttribute fills in m for a oneOf or anyOf schema. Each variant becomes
+// an optional nested attribute, and only one of them may be set.
+//
+// On the wire the value is the variant itself, without the wrapping object.
+func (g generator) oneOfAttribute(ctx context.Context, m *ResourceAttribute, prop *Schema, variants []Schema, required bool, computed bool, description string) error {
+	nested := make(map[string]*ResourceAttribute)
+	for i := range variants {
+		name := variantName(&variants[i], i)
+		if _, ok := nested[name]; ok {
+			name = fmt.Sprintf("%s_%d", name, i+1)
+		}
+		a, err := g.withField(name).schemaAttribute(ctx, &variants[i], name, nil)
+		if err != nil {
+			return fmt.Errorf("variant %s: %w", name, err)
+		}
+		if a == nil {
+			continue
+		}
+		nested[name] = a
+	}
+
+	names := make([]string, 0, len(nested))
+	for name := range nested {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	paragraphs := []string{fmt.Sprintf("Exactly one of `%s` must be set.", strings.Join(names, "`, `"))}
+	if description != "" {
+		paragraphs = append([]string{description}, paragraphs...)
+	}
+	description = strings.Join(paragraphs, "\n\n")
+
+	sensitive := prop.sensitive()
+	m.Type = ONE_OF
+	m.NestedAttributes = nested
+	m.Attribute = tfschema.SingleNestedAttribute{
+		Attributes:          convertToMap(nested),
+		MarkdownDescription: description,
+		DeprecationMessage:  deprecationMessage(prop),
+		Sensitive:           sensitive,
+		Computed:            computed,
+		Required:            required,
+		Optional:            !required,
+		Validators:          []validator.Object{oneOfValidator{variants: names}},
+		PlanModifiers:       objectPlanModifiers(prop, computed),
+	}
+	m.DatasourceAttribute = dsschema.SingleNestedAttribute{
+		Attributes:          convertToMapForDatasource(nested),
+		MarkdownDescription: description,
+		Sensitive:           sensitive,
+		Computed:            true,
+	}
+	return nil
+}
+
+// variantName returns the attribute name of the i-th variant: the name of the
+// schema it refers to, its title or its type, in that order.
+func variantName(variant *Schema, i int) string {
+	switch {
+	case strings.HasPrefix(variant.Ref, componentSchemaPrefix):
+		return terraformName(strings.TrimPrefix(variant.Ref, componentSchemaPrefix))
+	case variant.Title != "":
+		return terraformName(strings.ReplaceAll(variant.Title, " ", "_"))
+	case variant.Type != "" && variant.Type != "object":
+		return variant.Type
+	default:
+		return fmt.Sprintf("variant_%d", i+1)
+	}
+}
+
+// oneOfValidator checks that exactly one variant of a oneOf attribute is set.
+type oneOfValidator struct {
+	variants []string
+}
+
+func (v oneOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("exactly one of %s must be set", strings.Join(v.variants, ", "))
+}
+
+func (v oneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v oneOfValidator) ValidateObject(ctx context.Context, req validator.ObjectRequest, resp *validator.ObjectResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	var set []string
+	unknown := false
+	for name, value := range req.ConfigValue.Attributes() {
+		// An unknown variant may turn out to be null, so only known values
+		// count.
+		switch {
+		case value.IsUnknown():
+			unknown = true
+		case !value.IsNull():
+			set = append(set, name)
+		}
+	}
+	if len(set) > 1 {
+		sort.Strings(set)
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeCombinationDiagnostic(
+			req.Path,
+			fmt.Sprintf("Only one of %s may be set, got %s.", strings.Join(v.variants, ", "), strings.Join(set, ", ")),
+		))
+	}
+	if len(set) == 0 && !unknown {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeCombinationDiagnostic(
+			req.Path,
+			fmt.Sprintf("One of %s must be set.", strings.Join(v.variants, ", ")),
+		))
+	}
+}
@@ -0,0 +1,212 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/numberdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// The functions below return the framework default for the OpenAPI default of
+// prop, or nil if it has none.
+//
+// Defaults that plannedDefault rejects never get here, so the warnings below
+// only cover defaults the framework can't hold.
+
+// plannedDefault reports whether the default of prop can be planned: it must
+// match the type of prop, which must be a primitive or a list or set of
+// primitives.
+func (s *Spec) plannedDefault(prop *Schema) bool {
+	if prop.valueType() != "array" {
+		return defaultMatches(prop.Default, prop.valueType())
+	}
+	items, ok := prop.Default.([]interface{})
+	if !ok || prop.Items == nil {
+		return false
+	}
+	itemSchema, err := s.Dereference(prop.Items)
+	if err != nil {
+		return false
+	}
+	for _, item := range items {
+		if !defaultMatches(item, itemSchema.valueType()) {
+			return false
+		}
+	}
+	return true
+}
+
+// defaultMatches reports whether the JSON value v is a default of the
+// primitive type t.
+func defaultMatches(v interface{}, t string) bool {
+	switch t {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		_, ok := defaultInt64(v)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	default:
+		return false
+	}
+}
+
+func stringDefault(ctx context.Context, prop *Schema) defaults.String {
+	if prop.Default == nil {
+		return nil
+	}
+	s, ok := prop.Default.(string)
+	if !ok {
+		warnDefault(ctx, prop)
+		return nil
+	}
+	return stringdefault.StaticString(s)
+}
+
+func int64Default(ctx context.Context, prop *Schema) defaults.Int64 {
+	if prop.Default == nil {
+		return nil
+	}
+	i, ok := defaultInt64(prop.Default)
+	if !ok {
+		warnDefault(ctx, prop)
+		return nil
+	}
+	return int64default.StaticInt64(i)
+}
+
+func numberDefault(ctx context.Context, prop *Schema) defaults.Number {
+	if prop.Default == nil {
+		return nil
+	}
+	f, ok := prop.Default.(float64)
+	if !ok {
+		warnDefault(ctx, prop)
+		return nil
+	}
+	return numberdefault.StaticBigFloat(big.NewFloat(f))
+}
+
+func boolDefault(ctx context.Context, prop *Schema) defaults.Bool {
+	if prop.Default == nil {
+		return nil
+	}
+	b, ok := prop.Default.(bool)
+	if !ok {
+		warnDefault(ctx, prop)
+		return nil
+	}
+	return booldefault.StaticBool(b)
+}
+
+// listDefault supports lists of strings, numbers, integers and booleans.
+func listDefault(ctx context.Context, prop *Schema, elementType attr.Type) defaults.List {
+	elements, ok := defaultElements(ctx, prop, elementType)
+	if !ok {
+		return nil
+	}
+	list, diags := types.ListValue(elementType, elements)
+	if diags.HasError() {
+		warnDefault(ctx, prop)
+		return nil
+	}
+	return listdefault.StaticValue(list)
+}
+
+// setDefault supports sets of strings, numbers, integers and booleans.
+func setDefault(ctx context.Context, prop *Schema, elementType attr.Type) defaults.Set {
+	elements, ok := defaultElements(ctx, prop, elementType)
+	if !ok {
+		return nil
+	}
+	set, diags := types.SetValue(elementType, elements)
+	if diags.HasError() {
+		warnDefault(ctx, prop)
+		return nil
+	}
+	return setdefault.StaticValue(set)
+}
+
+// defaultElements converts the items of an array default. It reports false if
+// there is no default or it doesn't match elementType.
+func defaultElements(ctx context.Context, prop *Schema, elementType attr.Type) ([]attr.Value, bool) {
+	if prop.Default == nil {
+		return nil, false
+	}
+	items, ok := prop.Default.([]interface{})
+	if !ok {
+		warnDefault(ctx, prop)
+		return nil, false
+	}
+
+	elements := make([]attr.Value, 0, len(items))
+	for _, item := range items {
+		var element attr.Value
+		switch v := item.(type) {
+		case string:
+			element = types.StringValue(v)
+			if elementType.Equal(types.Int64Type) {
+				i, ok := defaultInt64(v)
+				if !ok {
+					warnDefault(ctx, prop)
+					return nil, false
+				}
+				element = types.Int64Value(i)
+			}
+		case bool:
+			element = types.BoolValue(v)
+		case float64:
+			if elementType.Equal(types.Int64Type) {
+				i, ok := defaultInt64(v)
+				if !ok {
+					warnDefault(ctx, prop)
+					return nil, false
+				}
+				element = types.Int64Value(i)
+			} else {
+				element = types.NumberValue(big.NewFloat(v))
+			}
+		default:
+			warnDefault(ctx, prop)
+			return nil, false
+		}
+		elements = append(elements, element)
+	}
+	return elements, true
+}
+
+// defaultInt64 converts a whole JSON number, or a string that holds one, to an
+// int64.
+func defaultInt64(v interface{}) (int64, bool) {
+	if s, ok := v.(string); ok {
+		i, err := strconv.ParseInt(s, 10, 64)
+		return i, err == nil
+	}
+	f, ok := v.(float64)
+	if !ok || f != math.Trunc(f) {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+func warnDefault(ctx context.Context, prop *Schema) {
+	tflog.Warn(ctx, fmt.Sprintf("ignoring default %v that doesn't match type %s", prop.Default, prop.Type))
+}
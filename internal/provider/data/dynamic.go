@@ -0,0 +1,203 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	tfschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// dynamicAttribute fills in m for the free-form value prop, if the spec
+// generates dynamic attributes and g allows them. It reports whether it did.
+//
+// The data source attribute is still a JSON string, since data sources list
+// resources and Terraform doesn't allow dynamic values in lists.
+func (g generator) dynamicAttribute(m *ResourceAttribute, prop *Schema, required bool, computed bool, description string) bool {
+	if !g.spec.DynamicValues || g.jsonStrings {
+		return false
+	}
+	sensitive := prop.sensitive()
+	m.Type = DYNAMIC
+	m.Attribute = tfschema.DynamicAttribute{
+		MarkdownDescription: description,
+		DeprecationMessage:  deprecationMessage(prop),
+		Sensitive:           sensitive,
+		Computed:            computed,
+		Required:            required,
+		Optional:            !required,
+		PlanModifiers:       dynamicPlanModifiers(prop, computed),
+	}
+	m.DatasourceAttribute = dsschema.StringAttribute{
+		CustomType:          jsontypes.NormalizedType{},
+		MarkdownDescription: description,
+		Sensitive:           sensitive,
+		Computed:            true,
+	}
+	return true
+}
+
+// dynamicToJSON returns the JSON value of v, the value of a dynamic attribute.
+func dynamicToJSON(v Value) interface{} {
+	switch {
+	case v.Boolean != nil:
+		return *v.Boolean
+	case v.Number != nil:
+		return json.Number(v.Number.Text('g', -1))
+	case v.String != nil:
+		return *v.String
+	case v.List != nil || v.Set != nil:
+		items := v.List
+		if items == nil {
+			items = v.Set
+		}
+		list := make([]interface{}, len(*items))
+		for i, item := range *items {
+			list[i] = dynamicToJSON(item)
+		}
+		return list
+	case v.Map != nil || v.Object != nil:
+		values := v.Map
+		if values == nil {
+			values = v.Object
+		}
+		object := make(map[string]interface{}, len(*values))
+		for key, value := range *values {
+			object[key] = dynamicToJSON(value)
+		}
+		return object
+	default:
+		return nil
+	}
+}
+
+// dynamicFromJSON returns the value of a dynamic attribute that holds the JSON
+// value v.
+func dynamicFromJSON(v interface{}) (Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return Value{}, nil
+	case bool:
+		return Value{Boolean: &v}, nil
+	case float64:
+		return Value{Number: big.NewFloat(v)}, nil
+	case json.Number:
+		f, _, err := big.ParseFloat(v.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return Value{}, fmt.Errorf("invalid number %s: %w", v, err)
+		}
+		return Value{Number: f}, nil
+	case string:
+		return Value{String: &v}, nil
+	case []interface{}:
+		list := make([]Value, len(v))
+		for i, item := range v {
+			converted, err := dynamicFromJSON(item)
+			if err != nil {
+				return Value{}, err
+			}
+			list[i] = converted
+		}
+		return Value{List: &list}, nil
+	case map[string]interface{}:
+		object := make(map[string]Value, len(v))
+		for key, value := range v {
+			converted, err := dynamicFromJSON(value)
+			if err != nil {
+				return Value{}, err
+			}
+			object[key] = converted
+		}
+		return Value{Object: &object}, nil
+	default:
+		return Value{}, fmt.Errorf("unexpected JSON value of type %T", v)
+	}
+}
+
+// dynamicToTerraform5Value converts v, the value of a dynamic attribute, with
+// the type of the HCL literal that would produce it: JSON objects are objects
+// and arrays are tuples.
+func dynamicToTerraform5Value(v Value) (tftypes.Value, error) {
+	switch {
+	case v.Boolean != nil:
+		return tftypes.NewValue(tftypes.Bool, v.Boolean), nil
+	case v.Number != nil:
+		return tftypes.NewValue(tftypes.Number, v.Number), nil
+	case v.String != nil:
+		return tftypes.NewValue(tftypes.String, v.String), nil
+	case v.List != nil || v.Set != nil:
+		items := v.List
+		if items == nil {
+			items = v.Set
+		}
+		elements := make([]tftypes.Value, len(*items))
+		elementTypes := make([]tftypes.Type, len(*items))
+		for i, item := range *items {
+			element, err := dynamicToTerraform5Value(item)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elements[i], elementTypes[i] = element, element.Type()
+		}
+		return tftypes.NewValue(tftypes.Tuple{ElementTypes: elementTypes}, elements), nil
+	case v.Map != nil || v.Object != nil:
+		values := v.Map
+		if values == nil {
+			values = v.Object
+		}
+		attributes := make(map[string]tftypes.Value, len(*values))
+		attributeTypes := make(map[string]tftypes.Type, len(*values))
+		for key, value := range *values {
+			attribute, err := dynamicToTerraform5Value(value)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			attributes[key], attributeTypes[key] = attribute, attribute.Type()
+		}
+		return tftypes.NewValue(tftypes.Object{AttributeTypes: attributeTypes}, attributes), nil
+	default:
+		return tftypes.NewValue(tftypes.DynamicPseudoType, nil), nil
+	}
+}
+
+// dynamicStateType returns the type of the JSON value v, as Terraform writes
+// it next to dynamic values in state.
+func dynamicStateType(v interface{}) interface{} {
+	switch v := v.(type) {
+	case bool:
+		return "bool"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		types := make([]interface{}, len(v))
+		for i, item := range v {
+			types[i] = dynamicStateType(item)
+		}
+		return []interface{}{"tuple", types}
+	case map[string]interface{}:
+		types := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			types[key] = dynamicStateType(value)
+		}
+		return []interface{}{"object", types}
+	default:
+		return "dynamic"
+	}
+}
+
+// dynamicStateValue returns the JSON value held by v, a dynamic value as
+// Terraform writes it in state. It reports false if v isn't one.
+func dynamicStateValue(v interface{}) (interface{}, bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) != 2 {
+		return nil, false
+	}
+	value, hasValue := m["value"]
+	_, hasType := m["type"]
+	return value, hasValue && hasType
+}
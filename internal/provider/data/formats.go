@@ -0,0 +1,94 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timetypes/timetypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/helpers/validatordiag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// stringCustomType returns the type of a string in the format of prop, or nil
+// for plain strings. Timestamps are compared by the instant they stand for, so
+// a server that formats them differently doesn't cause a diff.
+func stringCustomType(prop *Schema) basetypes.StringTypable {
+	if prop.Format == "date-time" {
+		return timetypes.RFC3339Type{}
+	}
+	return nil
+}
+
+// formatChecks holds the string formats that are checked at plan time.
+// date-time is checked by its custom type instead.
+var formatChecks = map[string]func(string) bool{
+	"duration": validDuration,
+	"uri":      validURI,
+	"email":    validEmail,
+	"ipv4": func(s string) bool {
+		addr, err := netip.ParseAddr(s)
+		return err == nil && addr.Is4()
+	},
+	"ipv6": func(s string) bool {
+		addr, err := netip.ParseAddr(s)
+		return err == nil && addr.Is6()
+	},
+	"uuid": uuidPattern.MatchString,
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// isoDurationPattern matches ISO 8601 durations, such as P1DT12H.
+var isoDurationPattern = regexp.MustCompile(`^P(\d+(\.\d+)?Y)?(\d+(\.\d+)?M)?(\d+(\.\d+)?W)?(\d+(\.\d+)?D)?(T(\d+(\.\d+)?H)?(\d+(\.\d+)?M)?(\d+(\.\d+)?S)?)?$`)
+
+// validDuration accepts ISO 8601 durations, as well as durations such as
+// "3.5s" or "1h30m", which is how google.protobuf.Duration is written in
+// JSON.
+func validDuration(s string) bool {
+	if _, err := time.ParseDuration(s); err == nil {
+		return true
+	}
+	return s != "P" && !strings.HasSuffix(s, "T") && isoDurationPattern.MatchString(s)
+}
+
+// validURI accepts absolute URIs.
+func validURI(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != ""
+}
+
+// validEmail accepts bare addresses, without a display name.
+func validEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+
+// formatValidator checks that a string is in an OpenAPI format.
+type formatValidator struct {
+	format string
+	valid  func(string) bool
+}
+
+func (v formatValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be a valid %s", v.format)
+}
+
+func (v formatValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v formatValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if value := req.ConfigValue.ValueString(); !v.valid(value) {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(req.Path, v.Description(ctx), value))
+	}
+}
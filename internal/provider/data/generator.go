@@ -0,0 +1,73 @@
+package data
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// generator holds the state of generating the attributes of a resource. It
+// is passed by value, so that a nested field sees what its parents recorded
+// without affecting its siblings.
+type generator struct {
+	spec *Spec
+
+	// refs are the schemas being expanded, outermost first.
+	refs []string
+
+	// path is the path of the field being generated, such as "spec.@type",
+	// or empty at the top level of the resource.
+	path string
+
+	// jsonStrings makes free-form values JSON strings even if the spec
+	// generates dynamic attributes. Terraform doesn't allow dynamic values
+	// inside lists, sets and maps.
+	jsonStrings bool
+
+	// diags collects the warnings about the generated schema.
+	diags *diag.Diagnostics
+}
+
+func newGenerator(spec *Spec, diags *diag.Diagnostics) generator {
+	return generator{spec: spec, diags: diags}
+}
+
+// withRef returns g recording that the schema ref is being expanded.
+func (g generator) withRef(ref string) generator {
+	g.refs = append(append([]string{}, g.refs...), ref)
+	return g
+}
+
+// withField returns g recording that the field name is being generated.
+func (g generator) withField(name string) generator {
+	g.path = g.fieldPath(name)
+	return g
+}
+
+// withJSONStrings returns g generating free-form values as JSON strings.
+func (g generator) withJSONStrings() generator {
+	g.jsonStrings = true
+	return g
+}
+
+// fieldPath returns the path of the field name inside the field being
+// generated.
+func (g generator) fieldPath(name string) string {
+	if g.path == "" {
+		return name
+	}
+	return g.path + "." + name
+}
+
+// currentField returns the path of the field being generated, or name at the
+// top level.
+func (g generator) currentField(name string) string {
+	if g.path == "" {
+		return name
+	}
+	return g.path
+}
+
+func (g generator) addWarning(summary string, detail string) {
+	if g.diags != nil {
+		g.diags.AddWarning(summary, detail)
+	}
+}
@@ -0,0 +1,74 @@
+package data
+
+import (
+	"context"
+
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	tfschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// mapAttribute fills in m for an object whose additionalProperties have the
+// schema values. It reports false if the values can't be typed, in which case
+// the object is left as free-form JSON.
+func (g generator) mapAttribute(ctx context.Context, m *ResourceAttribute, prop *Schema, values *Schema, required bool, computed bool, description string) (bool, error) {
+	item, t, err := g.itemAttribute(ctx, values, m.JSONName)
+	if err != nil {
+		return false, err
+	}
+	if item.Type == JSON_OBJECT {
+		return false, nil
+	}
+	resolved, err := g.spec.resolve(values)
+	if err != nil {
+		return false, err
+	}
+	sensitive := prop.sensitive()
+	m.Type = MAP
+	m.ListItemType = item.Type
+	m.ItemAttribute = item
+
+	if item.Type == OBJECT {
+		no := item.NestedAttributes
+		m.NestedAttributes = no
+		m.Attribute = tfschema.MapNestedAttribute{
+			NestedObject: tfschema.NestedAttributeObject{
+				Attributes: convertToMap(no),
+			},
+			MarkdownDescription: description,
+			DeprecationMessage:  deprecationMessage(prop),
+			Sensitive:           sensitive,
+			Computed:            computed,
+			Required:            required,
+			Optional:            !required,
+			PlanModifiers:       mapPlanModifiers(prop, computed),
+		}
+		m.DatasourceAttribute = dsschema.MapNestedAttribute{
+			NestedObject: dsschema.NestedAttributeObject{
+				Attributes: convertToMapForDatasource(no),
+			},
+			MarkdownDescription: description,
+			Sensitive:           sensitive,
+			Computed:            true,
+		}
+		return true, nil
+	}
+
+	m.Attribute = tfschema.MapAttribute{
+		ElementType:         t,
+		MarkdownDescription: description,
+		DeprecationMessage:  deprecationMessage(prop),
+		Sensitive:           sensitive,
+		Computed:            computed,
+		Required:            required,
+		Optional:            !required,
+		Validators:          mapValidators(ctx, resolved),
+		PlanModifiers:       mapPlanModifiers(prop, computed),
+	}
+	m.DatasourceAttribute = dsschema.MapAttribute{
+		ElementType:         t,
+		MarkdownDescription: description,
+		Sensitive:           sensitive,
+		Computed:            true,
+	}
+	return true, nil
+}
@@ -0,0 +1,91 @@
+package data
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// reservedNames are the meta-arguments Terraform reserves at the top level
+// of a resource. Fields with these names get a trailing underscore.
+var reservedNames = []string{"connection", "count", "depends_on", "for_each", "lifecycle", "provider", "provisioner"}
+
+var invalidNameChars = regexp.MustCompile("[^a-z0-9_]")
+
+// terraformName returns the attribute name of the JSON field name: snake case
+// without "@", with any character Terraform doesn't allow in names replaced
+// by "_".
+func terraformName(name string) string {
+	n := invalidNameChars.ReplaceAllString(strings.Replace(ToSnakeCase(name), "@", "", -1), "_")
+	if n == "" || (n[0] >= '0' && n[0] <= '9') {
+		n = "_" + n
+	}
+	return n
+}
+
+// uniqueNames keys attributes by their Terraform names, renaming attributes
+// whose names are taken.
+//
+// Of the fields that share a name, the one whose JSON name is already that
+// name keeps it, and otherwise the first by JSON name. The others get the
+// suffix _2, _3 and so on, in the order of their JSON names. Names in
+// reserved, such as meta-arguments, are escaped with a trailing underscore
+// first, and names in taken are never used.
+func (g generator) uniqueNames(attributes []*ResourceAttribute, reserved []string, taken map[string]bool) map[string]*ResourceAttribute {
+	sort.Slice(attributes, func(i, j int) bool {
+		a, b := attributes[i], attributes[j]
+		if a.TerraformName != b.TerraformName {
+			return a.TerraformName < b.TerraformName
+		}
+		if exact := a.JSONName == a.TerraformName; exact != (b.JSONName == b.TerraformName) {
+			return exact
+		}
+		return a.JSONName < b.JSONName
+	})
+
+	used := make(map[string]bool, len(taken)+len(attributes))
+	for name := range taken {
+		used[name] = true
+	}
+	// Names are handed out in two passes, so that a field is never renamed
+	// to the name another field has on its own.
+	m := make(map[string]*ResourceAttribute, len(attributes))
+	var collided []*ResourceAttribute
+	for _, a := range attributes {
+		if isReserved(a.TerraformName, reserved) {
+			a.TerraformName += "_"
+			g.addWarning("Attribute renamed", fmt.Sprintf(
+				"Field %q is the attribute %q, since %q is reserved by Terraform.",
+				g.fieldPath(a.JSONName), a.TerraformName, strings.TrimSuffix(a.TerraformName, "_")))
+		}
+		if used[a.TerraformName] {
+			collided = append(collided, a)
+			continue
+		}
+		used[a.TerraformName] = true
+		m[a.TerraformName] = a
+	}
+	for _, a := range collided {
+		base := a.TerraformName
+		for i := 2; used[a.TerraformName]; i++ {
+			a.TerraformName = base + "_" + strconv.Itoa(i)
+		}
+		used[a.TerraformName] = true
+		m[a.TerraformName] = a
+		g.addWarning("Attribute renamed", fmt.Sprintf(
+			"Field %q is the attribute %q, since %q is taken by another field.",
+			g.fieldPath(a.JSONName), a.TerraformName, base))
+	}
+	return m
+}
+
+func isReserved(name string, reserved []string) bool {
+	for _, r := range reserved {
+		if name == r {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package data
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aep-dev/aep-lib-go/pkg/api"
+)
+
+const namesTestSpec = `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "type": "object",
+        "x-aep-resource": {"singular": "thing"},
+        "properties": {
+          "displayName": {"type": "string"},
+          "display_name": {"type": "string"},
+          "@type": {"type": "string"},
+          "type": {"type": "string"},
+          "count": {"type": "integer"},
+          "2fa": {"type": "boolean"},
+          "page-count": {"type": "integer"},
+          "publisher": {"type": "string"},
+          "spec": {"$ref": "#/components/schemas/spec"}
+        }
+      },
+      "spec": {
+        "type": "object",
+        "properties": {
+          "fooBar": {"type": "string"},
+          "foo_bar": {"type": "string"},
+          "count": {"type": "integer"}
+        }
+      }
+    }
+  }
+}`
+
+// publisherPattern puts the resource under publishers, so that it has the
+// publisher parameter.
+func publisherPattern(r *api.Resource, _ *Spec) {
+	r.PatternElems = []string{"publishers", "{publisher}", "things", "{thing}"}
+}
+
+func TestAttributeNames(t *testing.T) {
+	s := newTestResourceSchema(t, namesTestSpec, publisherPattern)
+
+	tests := []struct {
+		// path is the Terraform path of the attribute.
+		path     string
+		jsonName string
+	}{
+		{"display_name", "display_name"},
+		{"display_name_2", "displayName"},
+		{"type", "type"},
+		{"type_2", "@type"},
+		{"count_", "count"},
+		{"_2fa", "2fa"},
+		{"page_count", "page-count"},
+		{"publisher", "publisher"},
+		{"publisher_2", "publisher"},
+		{"spec", "spec"},
+		// Meta-arguments are only reserved at the top level.
+		{"spec.foo_bar", "foo_bar"},
+		{"spec.foo_bar_2", "fooBar"},
+		{"spec.count", "count"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			names := strings.Split(tt.path, ".")
+			a := s.Attributes[names[0]]
+			for _, name := range names[1:] {
+				if a == nil {
+					break
+				}
+				a = a.NestedAttributes[name]
+			}
+			if a == nil {
+				t.Fatalf("no attribute %s for %s", tt.path, tt.jsonName)
+			}
+			if a.JSONName != tt.jsonName || a.TerraformName != names[len(names)-1] {
+				t.Errorf("attribute %s is %s named %s, want %s", tt.path, a.JSONName, a.TerraformName, tt.jsonName)
+			}
+			// Only the path parameter keeps the name publisher.
+			if a.Parameter != (tt.path == "publisher") {
+				t.Errorf("attribute %s has Parameter = %v", tt.path, a.Parameter)
+			}
+		})
+	}
+}
+
+func TestAttributeNameWarnings(t *testing.T) {
+	s := newTestResourceSchema(t, namesTestSpec, publisherPattern)
+
+	var details []string
+	for _, d := range s.Diagnostics {
+		details = append(details, d.Detail())
+	}
+	sort.Strings(details)
+	wantDetails := []string{
+		`Field "@type" is the attribute "type_2", since "type" is taken by another field.`,
+		`Field "count" is the attribute "count_", since "count" is reserved by Terraform.`,
+		`Field "displayName" is the attribute "display_name_2", since "display_name" is taken by another field.`,
+		`Field "publisher" is the attribute "publisher_2", since "publisher" is taken by another field.`,
+		`Field "spec.fooBar" is the attribute "foo_bar_2", since "foo_bar" is taken by another field.`,
+	}
+	if !reflect.DeepEqual(details, wantDetails) {
+		t.Errorf("warnings = %s, want %s", strings.Join(details, "\n"), strings.Join(wantDetails, "\n"))
+	}
+}
+
+func TestRenamedAttributeJSON(t *testing.T) {
+	s := newTestResourceSchema(t, namesTestSpec, publisherPattern)
+
+	// Renamed fields are sent with their JSON names.
+	str := "x"
+	got, err := ConvertValue(Value{Object: &map[string]Value{"foo_bar_2": {String: &str}}}, s.Attributes["spec"])
+	if err != nil {
+		t.Fatalf("ConvertValue() error = %v", err)
+	}
+	if want := map[string]interface{}{"fooBar": "x"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ConvertValue() = %v, want %v", got, want)
+	}
+}
+
+func TestAttributeNamesDeterministic(t *testing.T) {
+	first := newTestResourceSchema(t, namesTestSpec, publisherPattern)
+	for i := 0; i < 20; i++ {
+		s := newTestResourceSchema(t, namesTestSpec, publisherPattern)
+		for name, a := range first.Attributes {
+			if s.Attributes[name] == nil || s.Attributes[name].JSONName != a.JSONName {
+				t.Fatalf("attribute %s is %+v, was %s", name, s.Attributes[name], a.JSONName)
+			}
+		}
+	}
+}
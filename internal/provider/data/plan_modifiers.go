@@ -0,0 +1,110 @@
+package data
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/dynamicplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/numberplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+)
+
+// The functions below return the plan modifiers of an attribute.
+//
+// Immutable fields can't be updated, so changing them replaces the resource.
+// If the attribute is computed, the value in state is planned instead of an
+// unknown value, which would otherwise force a replacement on every update.
+
+func stringPlanModifiers(prop *Schema, computed bool) []planmodifier.String {
+	if !prop.immutable() {
+		return nil
+	}
+	if computed {
+		return []planmodifier.String{stringplanmodifier.UseStateForUnknown(), stringplanmodifier.RequiresReplace()}
+	}
+	return []planmodifier.String{stringplanmodifier.RequiresReplace()}
+}
+
+func int64PlanModifiers(prop *Schema, computed bool) []planmodifier.Int64 {
+	if !prop.immutable() {
+		return nil
+	}
+	if computed {
+		return []planmodifier.Int64{int64planmodifier.UseStateForUnknown(), int64planmodifier.RequiresReplace()}
+	}
+	return []planmodifier.Int64{int64planmodifier.RequiresReplace()}
+}
+
+func numberPlanModifiers(prop *Schema, computed bool) []planmodifier.Number {
+	if !prop.immutable() {
+		return nil
+	}
+	if computed {
+		return []planmodifier.Number{numberplanmodifier.UseStateForUnknown(), numberplanmodifier.RequiresReplace()}
+	}
+	return []planmodifier.Number{numberplanmodifier.RequiresReplace()}
+}
+
+func boolPlanModifiers(prop *Schema, computed bool) []planmodifier.Bool {
+	if !prop.immutable() {
+		return nil
+	}
+	if computed {
+		return []planmodifier.Bool{boolplanmodifier.UseStateForUnknown(), boolplanmodifier.RequiresReplace()}
+	}
+	return []planmodifier.Bool{boolplanmodifier.RequiresReplace()}
+}
+
+func listPlanModifiers(prop *Schema, computed bool) []planmodifier.List {
+	if !prop.immutable() {
+		return nil
+	}
+	if computed {
+		return []planmodifier.List{listplanmodifier.UseStateForUnknown(), listplanmodifier.RequiresReplace()}
+	}
+	return []planmodifier.List{listplanmodifier.RequiresReplace()}
+}
+
+func setPlanModifiers(prop *Schema, computed bool) []planmodifier.Set {
+	if !prop.immutable() {
+		return nil
+	}
+	if computed {
+		return []planmodifier.Set{setplanmodifier.UseStateForUnknown(), setplanmodifier.RequiresReplace()}
+	}
+	return []planmodifier.Set{setplanmodifier.RequiresReplace()}
+}
+
+func mapPlanModifiers(prop *Schema, computed bool) []planmodifier.Map {
+	if !prop.immutable() {
+		return nil
+	}
+	if computed {
+		return []planmodifier.Map{mapplanmodifier.UseStateForUnknown(), mapplanmodifier.RequiresReplace()}
+	}
+	return []planmodifier.Map{mapplanmodifier.RequiresReplace()}
+}
+
+func objectPlanModifiers(prop *Schema, computed bool) []planmodifier.Object {
+	if !prop.immutable() {
+		return nil
+	}
+	if computed {
+		return []planmodifier.Object{objectplanmodifier.UseStateForUnknown(), objectplanmodifier.RequiresReplace()}
+	}
+	return []planmodifier.Object{objectplanmodifier.RequiresReplace()}
+}
+
+func dynamicPlanModifiers(prop *Schema, computed bool) []planmodifier.Dynamic {
+	if !prop.immutable() {
+		return nil
+	}
+	if computed {
+		return []planmodifier.Dynamic{dynamicplanmodifier.UseStateForUnknown(), dynamicplanmodifier.RequiresReplace()}
+	}
+	return []planmodifier.Dynamic{dynamicplanmodifier.RequiresReplace()}
+}
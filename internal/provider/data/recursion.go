@@ -0,0 +1,85 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultRecursionDepth is the number of times a recursive schema is expanded
+// inside itself before the recursive field becomes a JSON string.
+const DefaultRecursionDepth = 1
+
+// refDepth returns the number of times the schema ref is already being
+// expanded.
+func (g generator) refDepth(ref string) int {
+	depth := 0
+	for _, r := range g.refs {
+		if r == ref {
+			depth++
+		}
+	}
+	return depth
+}
+
+// expandRef dereferences prop, the schema of the field name, for expansion.
+// The returned generator records the expansion.
+//
+// It reports false, with a warning, if prop refers to a schema that is
+// already being expanded RecursionDepth times. The field must then be kept as
+// JSON, as expanding it would never end.
+func (g generator) expandRef(prop *Schema, name string) (generator, *Schema, bool, error) {
+	if prop.Ref == "" {
+		return g, prop, true, nil
+	}
+	if g.refDepth(prop.Ref) > g.spec.RecursionDepth {
+		g.warnTruncated(prop.Ref, name)
+		return g, nil, false, nil
+	}
+	resolved, err := g.spec.Dereference(prop)
+	if err != nil {
+		return g, nil, false, err
+	}
+	return g.withRef(prop.Ref), resolved, true, nil
+}
+
+// expandAllOf flattens the allOf of prop, the schema of the field name, for
+// expansion. Like expandRef, the returned generator records the schemas the
+// allOf refers to, and it reports false, with a warning, if one of them is
+// already being expanded RecursionDepth times.
+func (g generator) expandAllOf(prop *Schema, name string) (generator, *Schema, bool, error) {
+	for _, part := range prop.AllOf {
+		if part.Ref != "" && g.refDepth(part.Ref) > g.spec.RecursionDepth {
+			g.warnTruncated(part.Ref, name)
+			return g, nil, false, nil
+		}
+	}
+	flattened, err := g.spec.FlattenAllOf(prop)
+	if err != nil {
+		return g, nil, false, err
+	}
+	for _, part := range prop.AllOf {
+		if part.Ref != "" {
+			g = g.withRef(part.Ref)
+		}
+	}
+	return g, flattened, true, nil
+}
+
+func (g generator) warnTruncated(ref string, name string) {
+	g.addWarning("Recursive schema truncated", fmt.Sprintf(
+		"Field %q refers to the schema %s, which contains itself. Past a depth of %d, it is a JSON string instead of nested attributes. Set AEP_SCHEMA_RECURSION_DEPTH to expand it further.",
+		g.currentField(name), strings.TrimPrefix(ref, componentSchemaPrefix), g.spec.RecursionDepth))
+}
+
+// truncatedSchema returns the schema of the recursive field prop once it is no
+// longer expanded: free-form JSON with the annotations of prop.
+func truncatedSchema(prop *Schema) *Schema {
+	truncated := (&Schema{Type: "object"}).withAnnotations(prop)
+	note := "This field is recursive, so at this depth it is a JSON string."
+	if truncated.Description == "" {
+		truncated.Description = note
+	} else {
+		truncated.Description += "\n\n" + note
+	}
+	return truncated
+}
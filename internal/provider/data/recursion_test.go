@@ -0,0 +1,125 @@
+package data
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aep-dev/aep-lib-go/pkg/api"
+)
+
+const recursiveTestSpec = `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "type": "object",
+        "properties": {
+          "root": {"$ref": "#/components/schemas/node"}
+        }
+      },
+      "node": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "children": {"type": "array", "items": {"$ref": "#/components/schemas/node"}},
+          "parent": {"$ref": "#/components/schemas/node", "description": "The parent node."},
+          "next": {"allOf": [{"$ref": "#/components/schemas/node"}]},
+          "siblings": {"type": "array", "items": {"allOf": [{"$ref": "#/components/schemas/node"}]}}
+        }
+      }
+    }
+  }
+}`
+
+// recursionDepth sets the RecursionDepth of the spec.
+func recursionDepth(depth int) testSchemaOption {
+	return func(_ *api.Resource, spec *Spec) {
+		spec.RecursionDepth = depth
+	}
+}
+
+func TestRecursiveSchema(t *testing.T) {
+	tests := []struct {
+		name  string
+		depth int
+		// path leads from the root attribute through nested attributes.
+		path []string
+		want TypeEnum
+	}{
+		{"root", 1, []string{"root"}, OBJECT},
+		{"children", 1, []string{"root", "children"}, OBJECT},
+		// The node inside the root is expanded once more. Its own children
+		// and parent are kept as JSON.
+		{"grandchildren", 1, []string{"root", "children", "children"}, JSON_OBJECT},
+		{"parent of parent", 1, []string{"root", "parent", "parent"}, JSON_OBJECT},
+		// References inside an allOf count towards the depth too.
+		{"next", 1, []string{"root", "next"}, OBJECT},
+		{"next of next", 1, []string{"root", "next", "next"}, JSON_OBJECT},
+		{"siblings", 1, []string{"root", "siblings"}, OBJECT},
+		{"siblings of siblings", 1, []string{"root", "siblings", "siblings"}, JSON_OBJECT},
+		{"children at depth 0", 0, []string{"root", "children"}, JSON_OBJECT},
+		{"parent at depth 3", 3, []string{"root", "parent", "parent", "parent"}, OBJECT},
+		{"parent at depth 4", 3, []string{"root", "parent", "parent", "parent", "parent"}, JSON_OBJECT},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestResourceSchema(t, recursiveTestSpec, recursionDepth(tt.depth))
+			a := s.Attributes[tt.path[0]]
+			for _, name := range tt.path[1:] {
+				a = a.NestedAttributes[name]
+			}
+			got := a.Type
+			if got == ARRAY {
+				got = a.ListItemType
+			}
+			if got != tt.want {
+				t.Errorf("%s is %s, want %s", strings.Join(tt.path, "."), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecursiveSchemaWarnings(t *testing.T) {
+	s := newTestResourceSchema(t, recursiveTestSpec, recursionDepth(1))
+
+	parent := s.Attributes["root"].NestedAttributes["parent"].NestedAttributes["parent"]
+	if description := parent.Attribute.GetMarkdownDescription(); !strings.HasPrefix(description, "The parent node.\n\nThis field is recursive") {
+		t.Errorf("parent of parent description = %q", description)
+	}
+
+	if len(s.Diagnostics) == 0 {
+		t.Fatal("no warning about the recursion")
+	}
+	paths := make(map[string]bool)
+	for _, d := range s.Diagnostics {
+		if !strings.Contains(d.Detail(), "node") {
+			t.Errorf("warning %q doesn't name the recursive schema", d.Detail())
+		}
+		paths[strings.SplitN(d.Detail(), `"`, 3)[1]] = true
+	}
+	// The warnings name the full path of the truncated fields.
+	for _, path := range []string{"root.parent.parent", "root.children.children"} {
+		if !paths[path] {
+			t.Errorf("no warning about %s, got warnings about %v", path, paths)
+		}
+	}
+}
+
+func TestFlattenAllOfCycle(t *testing.T) {
+	spec, err := ParseSpec([]byte(`{
+  "components": {
+    "schemas": {
+      "a": {"allOf": [{"$ref": "#/components/schemas/b"}]},
+      "b": {"allOf": [{"$ref": "#/components/schemas/a"}]}
+    }
+  }
+}`))
+	if err != nil {
+		t.Fatalf("ParseSpec() error = %v", err)
+	}
+	a := spec.Schemas["a"]
+	if _, err := spec.FlattenAllOf(&a); err == nil {
+		t.Error("FlattenAllOf() of a cycle succeeded")
+	}
+}
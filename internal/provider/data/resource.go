@@ -9,7 +9,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
@@ -109,18 +113,51 @@ func (r *Resource) ToJSON() (map[string]interface{}, error) {
 }
 
 func ConvertValue(v Value, a *ResourceAttribute) (interface{}, error) {
+	if a.Type == DYNAMIC {
+		return dynamicToJSON(v), nil
+	}
+	if a.Type == ONE_OF && v.Object != nil {
+		// Only the variant that is set is sent, without the wrapping object.
+		var variant *Value
+		var variantName string
+		for key, value := range *v.Object {
+			value := value
+			if value == (Value{}) {
+				continue
+			}
+			if variant != nil {
+				return nil, fmt.Errorf("only one of %s and %s may be set", variantName, key)
+			}
+			variant, variantName = &value, key
+		}
+		if variant == nil {
+			return nil, fmt.Errorf("one variant of %s must be set", a.TerraformName)
+		}
+		schemaObj, ok := a.NestedAttributes[variantName]
+		if !ok {
+			return nil, fmt.Errorf("variant %s not found", variantName)
+		}
+		return ConvertValue(*variant, schemaObj)
+	}
 	if v.Boolean != nil {
 		return *v.Boolean, nil
 	}
 	if v.Number != nil {
-		return v.Number.String(), nil
+		return numberToJSON(v.Number, a)
 	}
 	if v.String != nil {
 		if a.Type == JSON_OBJECT {
+			// Numbers are kept as json.Number, so that large integers
+			// reach the API exactly.
 			var parsed interface{}
-			if err := json.Unmarshal([]byte(*v.String), &parsed); err != nil {
+			d := json.NewDecoder(strings.NewReader(*v.String))
+			d.UseNumber()
+			if err := d.Decode(&parsed); err != nil {
 				return nil, fmt.Errorf("failed to parse JSON object string: %v", err)
 			}
+			if d.More() {
+				return nil, fmt.Errorf("failed to parse JSON object string: unexpected data after the value")
+			}
 			return parsed, nil
 		}
 		return *v.String, nil
@@ -128,7 +165,7 @@ func ConvertValue(v Value, a *ResourceAttribute) (interface{}, error) {
 	if v.List != nil {
 		list := make([]interface{}, len(*v.List))
 		for i, item := range *v.List {
-			v2, err := ConvertValue(item, a)
+			v2, err := ConvertValue(item, a.item())
 			if err != nil {
 				return nil, err
 			}
@@ -139,7 +176,7 @@ func ConvertValue(v Value, a *ResourceAttribute) (interface{}, error) {
 	if v.Map != nil {
 		mapJSON := make(map[string]interface{})
 		for key, value := range *v.Map {
-			convertedValue, err := ConvertValue(value, a)
+			convertedValue, err := ConvertValue(value, a.item())
 			if err != nil {
 				return nil, err
 			}
@@ -158,14 +195,14 @@ func ConvertValue(v Value, a *ResourceAttribute) (interface{}, error) {
 			if err != nil {
 				return nil, err
 			}
-			objectJSON[key] = convertedValue
+			objectJSON[schemaObj.JSONName] = convertedValue
 		}
 		return objectJSON, nil
 	}
 	if v.Set != nil {
 		set := make([]interface{}, len(*v.Set))
 		for i, item := range *v.Set {
-			convertedValue, err := ConvertValue(item, a)
+			convertedValue, err := ConvertValue(item, a.item())
 			if err != nil {
 				return nil, err
 			}
@@ -181,14 +218,27 @@ func ConvertValue(v Value, a *ResourceAttribute) (interface{}, error) {
 // The plan is used to provide type-conversion hints but does not gate inclusion.
 func FromJSON(m map[string]interface{}, r *Resource, plan *Resource) error {
 	for k, val := range r.Schema.Attributes {
-		v, ok := m[val.JSONName]
-		if !ok {
-			continue
-		}
 		planVal := Value{}
 		if pv, ok := plan.Values[k]; ok {
 			planVal = pv
 		}
+		// Parameters are part of the path rather than of the resource, and a
+		// field may have the same JSON name as one.
+		if val.Internal || val.Parameter {
+			continue
+		}
+		if val.WriteOnly {
+			// The server doesn't return write-only values, so the planned
+			// value is kept rather than letting the attribute flip to null.
+			if planVal != (Value{}) {
+				r.Values[k] = planVal
+			}
+			continue
+		}
+		v, ok := m[val.JSONName]
+		if !ok {
+			continue
+		}
 		convertedValue, err := ConvertTypeToValue(v, val, planVal)
 		if err != nil {
 			return err
@@ -212,18 +262,12 @@ func ConvertTypeToValue(v interface{}, r *ResourceAttribute, planValue Value) (V
 			return Value{}, fmt.Errorf("expected boolean, got %T", v)
 		}
 		return Value{Boolean: &b}, nil
-	case NUMBER:
-		num, ok := v.(float64)
-		if !ok {
-			return Value{}, fmt.Errorf("expected number, got %T", v)
-		}
-		return Value{Number: big.NewFloat(num)}, nil
-	case INTEGER:
-		intNum, ok := v.(int)
-		if !ok {
-			return Value{}, fmt.Errorf("expected integer, got %T", v)
+	case NUMBER, INTEGER:
+		num, err := numberFromJSON(v, r)
+		if err != nil {
+			return Value{}, err
 		}
-		return Value{Number: big.NewFloat(float64(intNum))}, nil
+		return Value{Number: num}, nil
 	case JSON_OBJECT:
 		jsonBytes, err := json.Marshal(v)
 		if err != nil {
@@ -231,6 +275,8 @@ func ConvertTypeToValue(v interface{}, r *ResourceAttribute, planValue Value) (V
 		}
 		str := string(jsonBytes)
 		return Value{String: &str}, nil
+	case DYNAMIC:
+		return dynamicFromJSON(v)
 	case OBJECT:
 		objectJSON := make(map[string]Value)
 		mapValue, ok := v.(map[string]interface{})
@@ -243,6 +289,9 @@ func ConvertTypeToValue(v interface{}, r *ResourceAttribute, planValue Value) (V
 			if schemaObj == nil {
 				return Value{}, fmt.Errorf("nested object name %s not found", key)
 			}
+			if schemaObj.WriteOnly {
+				continue
+			}
 			val := Value{}
 			if planValue.Object != nil {
 				if _, ok := (*planValue.Object)[schemaObj.TerraformName]; ok {
@@ -255,8 +304,50 @@ func ConvertTypeToValue(v interface{}, r *ResourceAttribute, planValue Value) (V
 			}
 			objectJSON[schemaObj.TerraformName] = convertedValue
 		}
+		if planValue.Object != nil {
+			for _, schemaObj := range r.NestedAttributes {
+				if pv, ok := (*planValue.Object)[schemaObj.TerraformName]; ok && schemaObj.WriteOnly {
+					objectJSON[schemaObj.TerraformName] = pv
+				}
+			}
+		}
 		return Value{Object: &objectJSON}, nil
-	case ARRAY:
+	case MAP:
+		if v == nil {
+			return Value{}, nil
+		}
+		mapValue, ok := v.(map[string]interface{})
+		if !ok {
+			return Value{}, fmt.Errorf("expected map, got %T", v)
+		}
+		values := make(map[string]Value, len(mapValue))
+		for key, item := range mapValue {
+			val := Value{}
+			if planValue.Map != nil {
+				val = (*planValue.Map)[key]
+			}
+			convertedValue, err := ConvertTypeToValue(item, r.item(), val)
+			if err != nil {
+				return Value{}, err
+			}
+			values[key] = convertedValue
+		}
+		return Value{Map: &values}, nil
+	case ONE_OF:
+		name, variant := matchVariant(v, r, planValue)
+		if variant == nil {
+			return Value{}, fmt.Errorf("%v matches no variant of %s", v, r.TerraformName)
+		}
+		val := Value{}
+		if planValue.Object != nil {
+			val = (*planValue.Object)[name]
+		}
+		convertedValue, err := ConvertTypeToValue(v, variant, val)
+		if err != nil {
+			return Value{}, err
+		}
+		return Value{Object: &map[string]Value{name: convertedValue}}, nil
+	case ARRAY, SET:
 		if v == nil {
 			return Value{}, nil
 		}
@@ -266,15 +357,145 @@ func ConvertTypeToValue(v interface{}, r *ResourceAttribute, planValue Value) (V
 		}
 		list := make([]Value, len(arrayValue))
 		for i, item := range arrayValue {
-			// TODO: Make the plan fetcher work with arrays.
-			convertedValue, err := ConvertTypeToValue(item, &ResourceAttribute{NestedAttributes: r.NestedAttributes, Type: r.ListItemType}, Value{})
+			// List items are matched to the plan by position. Sets have no
+			// order to match by.
+			val := Value{}
+			if planValue.List != nil && i < len(*planValue.List) {
+				val = (*planValue.List)[i]
+			}
+			convertedValue, err := ConvertTypeToValue(item, r.item(), val)
 			if err != nil {
 				return Value{}, err
 			}
 			list[i] = convertedValue
 		}
+		if r.Type == SET {
+			return Value{Set: &list}, nil
+		}
 		return Value{List: &list}, nil
 	default:
 		return Value{}, fmt.Errorf("cannot find type for %v", r)
 	}
 }
+
+// matchVariant returns the variant of a oneOf attribute that v holds.
+//
+// The variant set in the plan is preferred. Otherwise, the variant is picked
+// by the JSON type of v, and objects go to the variant that knows all of
+// their fields and shares the most with them. Free-form JSON variants take
+// whatever no other variant does.
+func matchVariant(v interface{}, r *ResourceAttribute, planValue Value) (string, *ResourceAttribute) {
+	if planValue.Object != nil {
+		for name, value := range *planValue.Object {
+			variant, ok := r.NestedAttributes[name]
+			if !ok || value == (Value{}) {
+				continue
+			}
+			if _, err := ConvertTypeToValue(v, variant, value); err == nil {
+				return name, variant
+			}
+		}
+	}
+
+	names := make([]string, 0, len(r.NestedAttributes))
+	for name := range r.NestedAttributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bestName, bestScore := "", -1
+	for _, name := range names {
+		variant := r.NestedAttributes[name]
+		if variant.Type == OBJECT {
+			object, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			score := 0
+			for key := range object {
+				if FindAttributeByJSONName(key, variant.NestedAttributes) == nil {
+					score = -1
+					break
+				}
+				score++
+			}
+			if score > bestScore {
+				bestName, bestScore = name, score
+			}
+			continue
+		}
+		if variant.freeForm() {
+			// Free-form JSON takes any value, so it is only the fallback.
+			continue
+		}
+		if bestScore < 0 {
+			if _, err := ConvertTypeToValue(v, variant, Value{}); err == nil {
+				bestName, bestScore = name, 0
+			}
+		}
+	}
+	if bestScore < 0 {
+		for _, name := range names {
+			if r.NestedAttributes[name].freeForm() {
+				return name, r.NestedAttributes[name]
+			}
+		}
+		return "", nil
+	}
+	return bestName, r.NestedAttributes[bestName]
+}
+
+// numberToJSON returns the JSON value of n, the value of a. Integers are sent
+// exactly, as JSON strings if a is string encoded. Other numbers are sent with
+// as many digits as they need.
+func numberToJSON(n *big.Float, a *ResourceAttribute) (interface{}, error) {
+	if a.Type != INTEGER {
+		if n.IsInt() {
+			return json.Number(n.Text('f', 0)), nil
+		}
+		return json.Number(n.Text('g', -1)), nil
+	}
+	i, accuracy := n.Int64()
+	if accuracy != big.Exact {
+		return nil, fmt.Errorf("%s is not a 64-bit integer", n.Text('g', -1))
+	}
+	if a.StringEncoded {
+		return strconv.FormatInt(i, 10), nil
+	}
+	return json.Number(strconv.FormatInt(i, 10)), nil
+}
+
+// numberFromJSON returns the number that the JSON value v holds, for the
+// NUMBER or INTEGER attribute a. Integers are also accepted as strings, as
+// APIs send int64 values.
+func numberFromJSON(v interface{}, a *ResourceAttribute) (*big.Float, error) {
+	var s string
+	switch v := v.(type) {
+	case json.Number:
+		s = v.String()
+	case float64:
+		if a.Type == INTEGER && v != math.Trunc(v) {
+			return nil, fmt.Errorf("expected integer, got %v", v)
+		}
+		return big.NewFloat(v), nil
+	case string:
+		if a.Type != INTEGER {
+			return nil, fmt.Errorf("expected number, got %T", v)
+		}
+		s = v
+	default:
+		return nil, fmt.Errorf("expected %s, got %T", a.Type, v)
+	}
+	if a.Type == INTEGER {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected integer, got %s", s)
+		}
+		return new(big.Float).SetInt64(i), nil
+	}
+	f, _, err := big.ParseFloat(s, 10, 512, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %s: %w", s, err)
+	}
+	return f, nil
+}
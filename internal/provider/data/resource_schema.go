@@ -8,9 +8,12 @@ import (
 
 	"github.com/aep-dev/aep-lib-go/pkg/api"
 	"github.com/aep-dev/aep-lib-go/pkg/openapi"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	tfschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -22,6 +25,19 @@ type ResourceSchema struct {
 
 	// Maps Terraform Name -> ResourceAttribute
 	Attributes map[string]*ResourceAttribute
+
+	// Version is the version of the resource's state schema. See
+	// schemaVersion.
+	Version int64
+
+	// Description is the description of the resource, and DeprecationMessage
+	// is set if the resource is deprecated.
+	Description        string
+	DeprecationMessage string
+
+	// Diagnostics holds the warnings from generating the schema, such as
+	// recursive fields that are kept as JSON.
+	Diagnostics diag.Diagnostics
 }
 
 func FindAttributeByJSONName(name string, attributes map[string]*ResourceAttribute) *ResourceAttribute {
@@ -42,7 +58,15 @@ const (
 	INTEGER     TypeEnum = "integer"
 	OBJECT      TypeEnum = "object"
 	ARRAY       TypeEnum = "array"
+	MAP         TypeEnum = "map"
 	JSON_OBJECT TypeEnum = "json_object"
+	// ONE_OF is a oneOf or anyOf, with one nested attribute per variant.
+	ONE_OF TypeEnum = "one_of"
+	// SET is an array with uniqueItems, whose order doesn't matter.
+	SET TypeEnum = "set"
+	// DYNAMIC is a free-form value held by a dynamic attribute, as opposed to
+	// a JSON_OBJECT string. See Spec.DynamicValues.
+	DYNAMIC TypeEnum = "dynamic"
 )
 
 type ResourceAttribute struct {
@@ -54,10 +78,26 @@ type ResourceAttribute struct {
 	Parameter bool
 	// If true, this is a read-only field.
 	Computed bool
+	// If true, the server never returns this field, so its value is kept from
+	// the plan.
+	WriteOnly bool
+	// If true, this attribute is managed by the provider and isn't part of the
+	// API resource.
+	Internal bool
+	// The field number from x-aep-field-numbers, if any.
+	FieldNumber *int
 	// The type of this resource attribute.
 	Type TypeEnum
-	// Only set for ARRAY types.
+	// If true, the INTEGER is a JSON string in the API. See
+	// Schema.stringEncoded.
+	StringEncoded bool
+	// The type of the items of ARRAY and SET types and of the values of MAP
+	// types.
 	ListItemType TypeEnum
+	// The items of ARRAY and SET types and the values of MAP types. It describes
+	// nested lists and maps, which ListItemType and NestedAttributes alone
+	// can't.
+	ItemAttribute *ResourceAttribute
 	// The attribute information for the resource.
 	Attribute tfschema.Attribute
 	// The attribute information for the data source.
@@ -85,7 +125,9 @@ func (r *ResourceSchema) FullCollectionDataSourceSchema(ctx context.Context) map
 
 	fullResource := make(map[string]dsschema.Attribute)
 	for _, attr := range r.Attributes {
-		fullResource[attr.TerraformName] = attr.DatasourceAttribute
+		if !attr.Internal {
+			fullResource[attr.TerraformName] = attr.DatasourceAttribute
+		}
 	}
 
 	// Results are stored here.
@@ -102,7 +144,7 @@ func (r *ResourceSchema) FullCollectionDataSourceSchema(ctx context.Context) map
 func (r *ResourceSchema) Parameters() map[string]tfschema.Attribute {
 	parameters := make(map[string]tfschema.Attribute)
 	for _, attr := range r.Attributes {
-		if attr.Parameter {
+		if attr.Parameter && !attr.Internal {
 			parameters[attr.TerraformName] = attr.Attribute
 		}
 	}
@@ -112,50 +154,104 @@ func (r *ResourceSchema) Parameters() map[string]tfschema.Attribute {
 func (r *ResourceSchema) SchemaAttributes() map[string]tfschema.Attribute {
 	schemaAttributes := make(map[string]tfschema.Attribute)
 	for _, attr := range r.Attributes {
-		if !attr.Parameter {
+		if !attr.Parameter && !attr.Internal {
 			schemaAttributes[attr.TerraformName] = attr.Attribute
 		}
 	}
 	return schemaAttributes
 }
 
+// JSONNames maps the Terraform name of every attribute in SchemaAttributes to
+// the name of its field in the API, which can differ when the field was
+// renamed for Terraform.
+func (r *ResourceSchema) JSONNames() map[string]string {
+	names := make(map[string]string)
+	for _, attr := range r.Attributes {
+		if !attr.Parameter && !attr.Internal {
+			names[attr.TerraformName] = attr.JSONName
+		}
+	}
+	return names
+}
+
+// NewResourceSchema generates the schema of r from the schemas parsed by
+// aep-lib-go. Use NewResourceSchemaFromSpec to take every keyword of the
+// document into account.
 func NewResourceSchema(ctx context.Context, r *api.Resource, o *openapi.OpenAPI) (*ResourceSchema, error) {
+	spec, err := SpecFromOpenAPI(o)
+	if err != nil {
+		return nil, err
+	}
+	return NewResourceSchemaFromSpec(ctx, r, spec)
+}
+
+// NewResourceSchemaFromSpec generates the schema of r from the component
+// schemas in spec.
+func NewResourceSchemaFromSpec(ctx context.Context, r *api.Resource, spec *Spec) (*ResourceSchema, error) {
 	schema := &ResourceSchema{
-		Resource:   r,
-		Attributes: make(map[string]*ResourceAttribute),
+		Resource: r,
 	}
 
-	// Add all normal schema attributes.
-	a := schemaAttributes(ctx, r.Schema, o)
-	for _, attr := range a {
-		schema.Attributes[attr.TerraformName] = attr
+	ref, resourceSchema, err := spec.resourceSchema(r)
+	if err != nil {
+		return nil, err
+	}
+	g := newGenerator(spec, &schema.Diagnostics)
+	if ref != "" {
+		g = g.withRef(ref)
+	}
+	resourceSchema, err = spec.FlattenAllOf(resourceSchema)
+	if err != nil {
+		return nil, err
+	}
+	schema.Description = resourceDescription(r, resourceSchema)
+	if resourceSchema.Deprecated {
+		schema.DeprecationMessage = "This resource is deprecated by the API and may be removed in a future version."
 	}
 
-	// Add all parameters.
+	// Parameters and the field numbers keep their names, and fields that
+	// would take them are renamed.
+	var parameters []string
 	if len(r.PatternElems) > 0 {
 		for _, elem := range r.PatternElems[:len(r.PatternElems)-1] {
 			if strings.HasPrefix(elem, "{") && strings.HasSuffix(elem, "}") {
-				paramName := strings.Replace(elem[1:len(elem)-1], "-", "_", -1)
-				schema.Attributes[paramName] = &ResourceAttribute{
-					TerraformName: paramName,
-					JSONName:      paramName,
-					Parameter:     true,
-					Type:          STRING,
-					Attribute: tfschema.StringAttribute{
-						MarkdownDescription: paramName,
-						Required:            true,
-						PlanModifiers: []planmodifier.String{
-							stringplanmodifier.RequiresReplace(),
-						},
-					},
-					DatasourceAttribute: dsschema.StringAttribute{
-						MarkdownDescription: paramName,
-						Required:            true,
-					},
-				}
+				parameters = append(parameters, strings.Replace(elem[1:len(elem)-1], "-", "_", -1))
 			}
 		}
 	}
+	taken := map[string]bool{FieldNumbersAttribute: true}
+	for _, paramName := range parameters {
+		taken[paramName] = true
+	}
+
+	// Add all normal schema attributes.
+	schema.Attributes = g.uniqueNames(g.schemaAttributeList(ctx, resourceSchema), reservedNames, taken)
+
+	// Add all parameters.
+	for _, paramName := range parameters {
+		schema.Attributes[paramName] = &ResourceAttribute{
+			TerraformName: paramName,
+			JSONName:      paramName,
+			Parameter:     true,
+			Type:          STRING,
+			Attribute: tfschema.StringAttribute{
+				MarkdownDescription: paramName,
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			DatasourceAttribute: dsschema.StringAttribute{
+				MarkdownDescription: paramName,
+				Required:            true,
+			},
+		}
+	}
+
+	if fieldNumbers := schema.fieldNumbers(); len(fieldNumbers) > 0 {
+		schema.Version = schemaVersion(r, resourceSchema, &schema.Diagnostics)
+		schema.Attributes[FieldNumbersAttribute] = fieldNumbersAttribute(fieldNumbers)
+	}
 
 	if _, ok := schema.Attributes["id"]; !ok {
 		if r.CreateMethod != nil && r.CreateMethod.SupportsUserSettableCreate {
@@ -195,6 +291,25 @@ func NewResourceSchema(ctx context.Context, r *api.Resource, o *openapi.OpenAPI)
 	return schema, nil
 }
 
+// resourceDescription returns the description of the resource r: the
+// description in its x-aep-resource extension, else that of its schema, else
+// its singular name.
+func resourceDescription(r *api.Resource, s *Schema) string {
+	var description string
+	switch {
+	case s.XAEPResource != nil && s.XAEPResource.Description != "":
+		description = s.XAEPResource.Description
+	case s.Description != "":
+		description = s.Description
+	default:
+		return r.Singular
+	}
+	if s.Title != "" && !strings.HasPrefix(description, s.Title) {
+		description = fmt.Sprintf("**%s**\n\n%s", s.Title, description)
+	}
+	return description
+}
+
 var matchFirstCap = regexp.MustCompile("(.)([A-Z][a-z]+)")
 var matchAllCap = regexp.MustCompile("([a-z0-9])([A-Z])")
 
@@ -204,27 +319,42 @@ func ToSnakeCase(str string) string {
 	return strings.ToLower(snake)
 }
 
-func schemaAttributes(ctx context.Context, s *openapi.Schema, o *openapi.OpenAPI) map[string]*ResourceAttribute {
-	m := make(map[string]*ResourceAttribute)
+// schemaAttributes returns the attributes of the properties of s, keyed by
+// their Terraform names. See uniqueNames for fields whose names collide.
+func (g generator) schemaAttributes(ctx context.Context, s *Schema) map[string]*ResourceAttribute {
+	return g.uniqueNames(g.schemaAttributeList(ctx, s), nil, nil)
+}
+
+func (g generator) schemaAttributeList(ctx context.Context, s *Schema) []*ResourceAttribute {
+	var attributes []*ResourceAttribute
+	fieldNumbers := make(map[string]int)
+	for number, name := range s.XAEPFieldNumbers {
+		fieldNumbers[name] = number
+	}
 	// Add all normal properties.
 	for name, prop := range s.Properties {
-		a, err := schemaAttribute(ctx, &prop, name, s.Required, o)
+		a, err := g.withField(name).schemaAttribute(ctx, &prop, name, s.Required)
 		if err != nil {
 			tflog.Error(ctx, fmt.Sprintf("could not create type for %s %v", name, prop))
 		} else if a != nil {
-			m[name] = a
+			if number, ok := fieldNumbers[name]; ok {
+				a.FieldNumber = &number
+			}
+			attributes = append(attributes, a)
 		}
 	}
-	return m
+	return attributes
 }
 
-func schemaAttribute(ctx context.Context, prop *openapi.Schema, name string, requiredProps []string, o *openapi.OpenAPI) (*ResourceAttribute, error) {
+func (g generator) schemaAttribute(ctx context.Context, prop *Schema, name string, requiredProps []string) (*ResourceAttribute, error) {
 	m := &ResourceAttribute{
-		TerraformName: strings.Replace(ToSnakeCase(name), "@", "", -1),
+		TerraformName: terraformName(name),
 		JSONName:      name,
 		Parameter:     false,
 		Computed:      prop.ReadOnly,
+		WriteOnly:     prop.WriteOnly,
 	}
+	sensitive := prop.sensitive()
 	required := checkIfRequired(requiredProps, name)
 
 	if name == "etag" {
@@ -232,34 +362,88 @@ func schemaAttribute(ctx context.Context, prop *openapi.Schema, name string, req
 	}
 
 	// GoogleProtobufValue is a type based on its name.
-	// It's just a string that stands in for arbitrary JSON.
+	// It stands in for arbitrary JSON, which is kept as a string.
 	if prop.Ref == "#/components/schemas/GoogleProtobufValue" {
-		m.Type = STRING
+		description := attributeDescription(prop)
+		if g.dynamicAttribute(m, prop, required, prop.ReadOnly, description) {
+			return m, nil
+		}
+		m.Type = JSON_OBJECT
 		m.Attribute = tfschema.StringAttribute{
-			MarkdownDescription: prop.Description,
+			CustomType:          jsontypes.NormalizedType{},
+			MarkdownDescription: description,
+			DeprecationMessage:  deprecationMessage(prop),
+			Sensitive:           sensitive,
 			Computed:            prop.ReadOnly,
 			Required:            required,
 			Optional:            !required,
+			PlanModifiers:       stringPlanModifiers(prop, prop.ReadOnly),
 		}
 		m.DatasourceAttribute = dsschema.StringAttribute{
-			MarkdownDescription: prop.Description,
+			CustomType:          jsontypes.NormalizedType{},
+			MarkdownDescription: description,
+			Sensitive:           sensitive,
 			Computed:            true,
 		}
 		return m, nil
 	}
 
 	if prop.Ref != "" {
-		s, err := o.DereferenceSchema(*prop)
+		g, s, ok, err := g.expandRef(prop, name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return g.withJSONStrings().schemaAttribute(ctx, truncatedSchema(prop), name, requiredProps)
+		}
+		return g.schemaAttribute(ctx, s.withAnnotations(prop), name, requiredProps)
+	}
+
+	if len(prop.AllOf) > 0 {
+		g, flattened, ok, err := g.expandAllOf(prop, name)
 		if err != nil {
 			return nil, err
 		}
-		if s == nil {
-			return nil, fmt.Errorf("ref not found for %s", prop.Ref)
+		if !ok {
+			return g.withJSONStrings().schemaAttribute(ctx, truncatedSchema(prop), name, requiredProps)
 		}
-		return schemaAttribute(ctx, s, name, requiredProps, o)
+		return g.schemaAttribute(ctx, flattened, name, requiredProps)
+	}
+
+	variants := prop.variants()
+	if len(variants) == 1 {
+		// A oneOf with a single variant besides "null" is just nullable.
+		return g.schemaAttribute(ctx, variants[0].withAnnotations(prop), name, requiredProps)
+	}
+
+	// A default that can't be planned is dropped, rather than leaving a
+	// computed attribute that is unknown on every plan.
+	if prop.Default != nil && !g.spec.plannedDefault(prop) {
+		g.addWarning("Default ignored", fmt.Sprintf(
+			"Field %q has the default %s, which doesn't match its type %q.",
+			g.currentField(name), formatDefault(prop.Default), prop.valueType()))
+		withoutDefault := *prop
+		withoutDefault.Default = nil
+		prop = &withoutDefault
+	}
+
+	// The description and the validators read the enum, lengths and ranges
+	// of the items, which may be a reference. The items themselves are
+	// expanded from prop, which keeps track of the recursion.
+	resolved, err := g.spec.resolveItems(prop)
+	if err != nil {
+		return nil, err
 	}
 
 	computed := prop.ReadOnly
+	description := attributeDescription(resolved)
+
+	// Attributes with a default can be left out of the config. The default is
+	// then planned, and what the server returns is accepted.
+	if prop.Default != nil {
+		required = false
+		computed = true
+	}
 
 	// The path field should always be treated as computed.
 	// If the ID is settable, the ID field will be used.
@@ -269,126 +453,203 @@ func schemaAttribute(ctx context.Context, prop *openapi.Schema, name string, req
 		m.Computed = true
 	}
 
-	switch prop.Type {
+	if len(variants) > 1 {
+		if err := g.oneOfAttribute(ctx, m, prop, variants, required, computed, description); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+
+	if (prop.Type == "object" || prop.Type == "") && len(prop.Properties) == 0 {
+		if values := prop.additionalProperties(); values != nil {
+			ok, err := g.mapAttribute(ctx, m, prop, values, required, computed, description)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				return m, nil
+			}
+		}
+	}
+
+	switch prop.valueType() {
 	case "number":
 		m.Type = NUMBER
 		m.Attribute = tfschema.NumberAttribute{
-			MarkdownDescription: prop.Description,
+			MarkdownDescription: description,
+			DeprecationMessage:  deprecationMessage(prop),
+			Sensitive:           sensitive,
 			Computed:            computed,
 			Required:            required,
 			Optional:            !required,
+			Validators:          numberValidators(prop),
+			Default:             numberDefault(ctx, prop),
+			PlanModifiers:       numberPlanModifiers(prop, computed),
 		}
 		m.DatasourceAttribute = dsschema.NumberAttribute{
-			MarkdownDescription: prop.Description,
+			MarkdownDescription: description,
+			Sensitive:           sensitive,
 			Computed:            true,
 		}
 	case "string":
 		m.Type = STRING
 		m.Attribute = tfschema.StringAttribute{
-			MarkdownDescription: prop.Description,
+			CustomType:          stringCustomType(prop),
+			MarkdownDescription: description,
+			DeprecationMessage:  deprecationMessage(prop),
+			Sensitive:           sensitive,
 			Computed:            computed,
 			Optional:            !required,
 			Required:            required,
+			Validators:          stringValidators(ctx, prop),
+			Default:             stringDefault(ctx, prop),
+			PlanModifiers:       stringPlanModifiers(prop, computed),
 		}
 		m.DatasourceAttribute = dsschema.StringAttribute{
-			MarkdownDescription: prop.Description,
+			CustomType:          stringCustomType(prop),
+			MarkdownDescription: description,
+			Sensitive:           sensitive,
 			Computed:            true,
 		}
 	case "boolean":
 		m.Type = BOOLEAN
 		m.Attribute = tfschema.BoolAttribute{
-			MarkdownDescription: prop.Description,
+			MarkdownDescription: description,
+			DeprecationMessage:  deprecationMessage(prop),
+			Sensitive:           sensitive,
 			Computed:            computed,
 			Required:            required,
 			Optional:            !required,
+			Default:             boolDefault(ctx, prop),
+			PlanModifiers:       boolPlanModifiers(prop, computed),
 		}
 		m.DatasourceAttribute = dsschema.BoolAttribute{
-			MarkdownDescription: prop.Description,
+			MarkdownDescription: description,
+			Sensitive:           sensitive,
 			Computed:            true,
 		}
 	case "integer":
 		m.Type = INTEGER
+		m.StringEncoded = prop.stringEncoded()
 		m.Attribute = tfschema.Int64Attribute{
-			MarkdownDescription: prop.Description,
+			MarkdownDescription: description,
+			DeprecationMessage:  deprecationMessage(prop),
+			Sensitive:           sensitive,
 			Computed:            computed,
 			Required:            required,
 			Optional:            !required,
+			Validators:          int64Validators(prop),
+			Default:             int64Default(ctx, prop),
+			PlanModifiers:       int64PlanModifiers(prop, computed),
 		}
 		m.DatasourceAttribute = dsschema.Int64Attribute{
-			MarkdownDescription: prop.Description,
+			MarkdownDescription: description,
+			Sensitive:           sensitive,
 			Computed:            true,
 		}
 	case "object":
 		if len(prop.Properties) == 0 {
+			if g.dynamicAttribute(m, prop, required, computed, description) {
+				return m, nil
+			}
 			m.Type = JSON_OBJECT
 			m.Attribute = tfschema.StringAttribute{
-				MarkdownDescription: prop.Description,
+				CustomType:          jsontypes.NormalizedType{},
+				MarkdownDescription: description,
+				DeprecationMessage:  deprecationMessage(prop),
+				Sensitive:           sensitive,
 				Computed:            computed,
 				Required:            required,
 				Optional:            !required,
+				PlanModifiers:       stringPlanModifiers(prop, computed),
 			}
 			m.DatasourceAttribute = dsschema.StringAttribute{
-				MarkdownDescription: prop.Description,
+				CustomType:          jsontypes.NormalizedType{},
+				MarkdownDescription: description,
+				Sensitive:           sensitive,
 				Computed:            true,
 			}
 		} else {
 			m.Type = OBJECT
-			no := schemaAttributes(ctx, prop, o)
+			no := g.schemaAttributes(ctx, prop)
 			m.Attribute = tfschema.SingleNestedAttribute{
 				Attributes:          convertToMap(no),
-				MarkdownDescription: prop.Description,
+				MarkdownDescription: description,
+				DeprecationMessage:  deprecationMessage(prop),
+				Sensitive:           sensitive,
 				Computed:            computed,
 				Required:            required,
 				Optional:            !required,
+				PlanModifiers:       objectPlanModifiers(prop, computed),
 			}
 			m.DatasourceAttribute = dsschema.SingleNestedAttribute{
 				Attributes:          convertToMapForDatasource(no),
-				MarkdownDescription: prop.Description,
+				MarkdownDescription: description,
+				Sensitive:           sensitive,
 				Computed:            true,
 			}
 			m.NestedAttributes = no
 		}
 	case "array":
 		m.Type = ARRAY
-		if prop.Items.Type == "object" {
-			m.ListItemType = OBJECT
-			no := schemaAttributes(ctx, prop.Items, o)
+		if prop.Items == nil {
+			return nil, fmt.Errorf("array %s has no items", name)
+		}
+		item, t, err := g.itemAttribute(ctx, prop.Items, name)
+		if err != nil {
+			return nil, err
+		}
+		m.ItemAttribute = item
+		m.ListItemType = item.Type
+		if prop.UniqueItems {
+			setAttribute(ctx, m, resolved, item, t, required, computed, description)
+		} else if item.Type == OBJECT {
+			no := item.NestedAttributes
 			m.NestedAttributes = no
 			m.Attribute = tfschema.ListNestedAttribute{
 				NestedObject: tfschema.NestedAttributeObject{
 					Attributes: convertToMap(no),
 				},
-				MarkdownDescription: prop.Description,
+				MarkdownDescription: description,
+				DeprecationMessage:  deprecationMessage(prop),
+				Sensitive:           sensitive,
 				Computed:            computed,
 				Required:            required,
 				Optional:            !required,
+				Validators:          listValidators(ctx, resolved),
+				PlanModifiers:       listPlanModifiers(prop, computed),
 			}
 			m.DatasourceAttribute = dsschema.ListNestedAttribute{
 				NestedObject: dsschema.NestedAttributeObject{
 					Attributes: convertToMapForDatasource(no),
 				},
-				MarkdownDescription: prop.Description,
+				MarkdownDescription: description,
+				Sensitive:           sensitive,
 				Computed:            true,
 			}
 		} else {
-			t, err := listType(prop)
-			if err != nil {
-				return nil, err
+			// Defaults are only planned for lists of primitives.
+			var listDefaultValue defaults.List
+			if item.ItemAttribute == nil && item.NestedAttributes == nil && item.Type != JSON_OBJECT {
+				listDefaultValue = listDefault(ctx, prop, t)
 			}
-			t2, err := listEnumType(prop)
-			if err != nil {
-				return nil, err
-			}
-			m.ListItemType = t2
 			m.Attribute = tfschema.ListAttribute{
-				ElementType: t,
-				Computed:    computed,
-				Required:    required,
-				Optional:    !required,
+				ElementType:         t,
+				MarkdownDescription: description,
+				DeprecationMessage:  deprecationMessage(prop),
+				Sensitive:           sensitive,
+				Computed:            computed,
+				Required:            required,
+				Optional:            !required,
+				Validators:          listValidators(ctx, resolved),
+				Default:             listDefaultValue,
+				PlanModifiers:       listPlanModifiers(prop, computed),
 			}
 			m.DatasourceAttribute = dsschema.ListAttribute{
-				ElementType: t,
-				Computed:    true,
+				ElementType:         t,
+				MarkdownDescription: description,
+				Sensitive:           sensitive,
+				Computed:            true,
 			}
 		}
 	default:
@@ -398,23 +659,120 @@ func schemaAttribute(ctx context.Context, prop *openapi.Schema, name string, req
 	return m, nil
 }
 
-func listType(prop *openapi.Schema) (attr.Type, error) {
-	switch prop.Items.Type {
+// freeForm reports whether a holds a free-form value, as a JSON string or a
+// dynamic value.
+func (a *ResourceAttribute) freeForm() bool {
+	return a.Type == JSON_OBJECT || a.Type == DYNAMIC
+}
+
+// item returns the attribute of the items of an ARRAY or SET, or the values of
+// a MAP.
+func (a *ResourceAttribute) item() *ResourceAttribute {
+	if a.ItemAttribute != nil {
+		return a.ItemAttribute
+	}
+	return &ResourceAttribute{NestedAttributes: a.NestedAttributes, Type: a.ListItemType}
+}
+
+// itemAttribute returns the attribute and the Terraform type of the items of
+// a list or the values of a map, described by items. Lists and maps nest to
+// any depth, and items that aren't typed are normalized JSON strings. name is
+// the name of the field the items belong to.
+func (g generator) itemAttribute(ctx context.Context, items *Schema, name string) (*ResourceAttribute, attr.Type, error) {
+	g, items, ok, err := g.expandRef(items, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return &ResourceAttribute{Type: JSON_OBJECT}, jsontypes.NormalizedType{}, nil
+	}
+	g, items, ok, err = g.expandAllOf(items, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return &ResourceAttribute{Type: JSON_OBJECT}, jsontypes.NormalizedType{}, nil
+	}
+
+	switch items.Type {
+	case "array":
+		if items.Items == nil {
+			return nil, nil, fmt.Errorf("nested array has no items")
+		}
+		inner, t, err := g.itemAttribute(ctx, items.Items, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		a := &ResourceAttribute{
+			Type:             ARRAY,
+			ListItemType:     inner.Type,
+			NestedAttributes: inner.NestedAttributes,
+			ItemAttribute:    inner,
+		}
+		if items.UniqueItems {
+			a.Type = SET
+			return a, types.SetType{ElemType: t}, nil
+		}
+		return a, types.ListType{ElemType: t}, nil
+	case "object", "":
+		if len(items.Properties) > 0 {
+			no := g.withJSONStrings().schemaAttributes(ctx, items)
+			attrTypes := make(map[string]attr.Type, len(no))
+			for _, a := range no {
+				attrTypes[a.TerraformName] = a.Attribute.GetType()
+			}
+			return &ResourceAttribute{Type: OBJECT, NestedAttributes: no}, types.ObjectType{AttrTypes: attrTypes}, nil
+		}
+		if values := items.additionalProperties(); values != nil {
+			inner, t, err := g.itemAttribute(ctx, values, name)
+			if err != nil {
+				return nil, nil, err
+			}
+			if inner.Type != JSON_OBJECT {
+				return &ResourceAttribute{
+					Type:             MAP,
+					ListItemType:     inner.Type,
+					NestedAttributes: inner.NestedAttributes,
+					ItemAttribute:    inner,
+				}, types.MapType{ElemType: t}, nil
+			}
+		}
+		return &ResourceAttribute{Type: JSON_OBJECT}, jsontypes.NormalizedType{}, nil
+	default:
+		t, err := elementType(items)
+		if err != nil {
+			return nil, nil, err
+		}
+		t2, err := elementEnumType(items)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &ResourceAttribute{Type: t2, StringEncoded: items.stringEncoded()}, t, nil
+	}
+}
+
+// elementType returns the Terraform type of the items of a list or the values
+// of a map.
+func elementType(prop *Schema) (attr.Type, error) {
+	switch prop.valueType() {
 	case "number":
 		return types.NumberType, nil
 	case "string":
+		if t := stringCustomType(prop); t != nil {
+			return t, nil
+		}
 		return types.StringType, nil
 	case "boolean":
 		return types.BoolType, nil
 	case "integer":
 		return types.Int64Type, nil
 	default:
-		return nil, fmt.Errorf("cannot find type for %s", prop.Items.Type)
+		return nil, fmt.Errorf("cannot find type for %s", prop.Type)
 	}
 }
 
-func listEnumType(prop *openapi.Schema) (TypeEnum, error) {
-	switch prop.Items.Type {
+func elementEnumType(prop *Schema) (TypeEnum, error) {
+	switch prop.valueType() {
 	case "string":
 		return STRING, nil
 	case "number":
@@ -424,7 +782,7 @@ func listEnumType(prop *openapi.Schema) (TypeEnum, error) {
 	case "integer":
 		return INTEGER, nil
 	default:
-		return "", fmt.Errorf("cannot find type for %s", prop.Items.Type)
+		return "", fmt.Errorf("cannot find type for %s", prop.Type)
 	}
 }
 
@@ -2,14 +2,27 @@ package data
 
 import (
 	"context"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/aep-dev/aep-lib-go/pkg/api"
 	"github.com/aep-dev/aep-lib-go/pkg/openapi"
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timetypes/timetypes"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
 	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	tfschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -224,6 +237,7 @@ func TestSchemaAttributes(t *testing.T) {
 						Parameter:     false,
 						Type:          ARRAY,
 						ListItemType:  STRING,
+						ItemAttribute: &ResourceAttribute{Type: STRING},
 						Attribute: tfschema.ListAttribute{
 							MarkdownDescription: "",
 							Optional:            true,
@@ -269,25 +283,27 @@ func TestSchemaAttributes(t *testing.T) {
 	}
 }
 
-func TestListType(t *testing.T) {
+func TestElementType(t *testing.T) {
 	tests := map[string]struct {
-		prop    openapi.Schema
+		prop    Schema
 		want    attr.Type
 		wantErr bool
 	}{
 		"string": {
-			prop: openapi.Schema{
-				Items: &openapi.Schema{
-					Type: "string",
-				},
+			prop: Schema{
+				Type: "string",
 			},
 			want: types.StringType,
 		},
+		"integer": {
+			prop: Schema{
+				Type: "integer",
+			},
+			want: types.Int64Type,
+		},
 		"unknown": {
-			prop: openapi.Schema{
-				Items: &openapi.Schema{
-					Type: "unknown",
-				},
+			prop: Schema{
+				Type: "unknown",
 			},
 			wantErr: true,
 		},
@@ -295,14 +311,1077 @@ func TestListType(t *testing.T) {
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			got, err := listType(&tt.prop)
+			got, err := elementType(&tt.prop)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("listType() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("elementType() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if diff := cmp.Diff(got, tt.want); diff != "" {
-				t.Errorf("listType() mismatch (-got +want):\n%s", diff)
+				t.Errorf("elementType() mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+// testSchemaOption changes the resource or the spec that
+// newTestResourceSchema generates the schema from.
+type testSchemaOption func(r *api.Resource, spec *Spec)
+
+// newTestResourceSchema generates the schema of the "thing" resource in the
+// spec document doc.
+func newTestResourceSchema(t *testing.T, doc string, options ...testSchemaOption) *ResourceSchema {
+	t.Helper()
+	spec, err := ParseSpec([]byte(doc))
+	if err != nil {
+		t.Fatalf("ParseSpec() error = %v", err)
+	}
+	r := &api.Resource{
+		Singular:     "thing",
+		Schema:       &openapi.Schema{},
+		PatternElems: []string{},
+		CreateMethod: &api.CreateMethod{},
+	}
+	for _, option := range options {
+		option(r, spec)
+	}
+	got, err := NewResourceSchemaFromSpec(context.TODO(), r, spec)
+	if err != nil {
+		t.Fatalf("NewResourceSchemaFromSpec() error = %v", err)
+	}
+	return got
+}
+
+func TestEnumValidators(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "properties": {
+          "color": {"type": "string", "description": "The color.", "enum": ["RED", "GREEN"]},
+          "size": {"type": "integer", "enum": [1, 2, 3]},
+          "tags": {"type": "array", "items": {"type": "string", "enum": ["A", "B"]}},
+          "tag_refs": {"type": "array", "items": {"$ref": "#/components/schemas/tag"}},
+          "labels": {"type": "object", "additionalProperties": {"$ref": "#/components/schemas/tag"}},
+          "name": {"type": "string"}
+        }
+      },
+      "tag": {"type": "string", "enum": ["A", "B"]}
+    }
+  }
+}`)
+
+	color := s.Attributes["color"].Attribute.(tfschema.StringAttribute)
+	if want := "The color.\n\nMust be one of: `RED`, `GREEN`."; color.MarkdownDescription != want {
+		t.Errorf("color description = %q, want %q", color.MarkdownDescription, want)
+	}
+	if diags := validateString(color.Validators, types.StringValue("BLUE")); !diags.HasError() {
+		t.Error("color accepted a value outside the enum")
+	}
+	if diags := validateString(color.Validators, types.StringValue("RED")); diags.HasError() {
+		t.Errorf("color rejected RED: %v", diags)
+	}
+
+	size := s.Attributes["size"].Attribute.(tfschema.Int64Attribute)
+	if want := "Must be one of: `1`, `2`, `3`."; size.MarkdownDescription != want {
+		t.Errorf("size description = %q, want %q", size.MarkdownDescription, want)
+	}
+	if len(size.Validators) != 1 {
+		t.Fatalf("size has %d validators, want 1", len(size.Validators))
+	}
+	if diags := validateInt64(size.Validators, types.Int64Value(4)); !diags.HasError() {
+		t.Error("size accepted a value outside the enum")
+	}
+
+	tags := s.Attributes["tags"].Attribute.(tfschema.ListAttribute)
+	if want := "Must be one of: `A`, `B`."; tags.MarkdownDescription != want {
+		t.Errorf("tags description = %q, want %q", tags.MarkdownDescription, want)
+	}
+	if len(tags.Validators) != 1 {
+		t.Fatalf("tags has %d validators, want 1", len(tags.Validators))
+	}
+	list, _ := types.ListValueFrom(context.TODO(), types.StringType, []string{"A", "C"})
+	if diags := validateList(tags.Validators, list); !diags.HasError() {
+		t.Error("tags accepted an item outside the enum")
+	}
+
+	// Items and map values that are references are validated against the
+	// schema they refer to.
+	tagRefs := s.Attributes["tag_refs"].Attribute.(tfschema.ListAttribute)
+	if want := "Must be one of: `A`, `B`."; tagRefs.MarkdownDescription != want {
+		t.Errorf("tag_refs description = %q, want %q", tagRefs.MarkdownDescription, want)
+	}
+	if diags := validateList(tagRefs.Validators, list); !diags.HasError() {
+		t.Error("tag_refs accepted an item outside the enum")
+	}
+	labels := s.Attributes["labels"].Attribute.(tfschema.MapAttribute)
+	labelValues, _ := types.MapValueFrom(context.TODO(), types.StringType, map[string]string{"a": "A", "c": "C"})
+	if diags := validateMap(labels.Validators, labelValues); !diags.HasError() {
+		t.Error("labels accepted a value outside the enum")
+	}
+
+	if name := s.Attributes["name"].Attribute.(tfschema.StringAttribute); len(name.Validators) != 0 {
+		t.Errorf("name has %d validators, want none", len(name.Validators))
+	}
+}
+
+func validateString(validators []validator.String, v types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, val := range validators {
+		resp := &validator.StringResponse{}
+		val.ValidateString(context.TODO(), validator.StringRequest{ConfigValue: v}, resp)
+		diags.Append(resp.Diagnostics...)
+	}
+	return diags
+}
+
+func TestRangeValidators(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "properties": {
+          "code": {"type": "string", "minLength": 2, "maxLength": 4, "pattern": "^[A-Z]+$"},
+          "count": {"type": "integer", "minimum": 1, "exclusiveMaximum": 10},
+          "ratio": {"type": "number", "minimum": 0, "exclusiveMinimum": true, "maximum": 1},
+          "names": {"type": "array", "minItems": 1, "maxItems": 2, "items": {"type": "string", "maxLength": 3}},
+          "owner": {"type": "object", "properties": {"email": {"type": "string", "minLength": 3}}},
+          "codes": {"type": "array", "uniqueItems": true, "items": {"$ref": "#/components/schemas/code"}},
+          "scores": {"type": "object", "additionalProperties": {"allOf": [{"$ref": "#/components/schemas/score"}]}}
+        }
+      },
+      "code": {"type": "string", "minLength": 2, "maxLength": 4, "pattern": "^[A-Z]+$"},
+      "score": {"type": "integer", "minimum": 1, "exclusiveMaximum": 10}
+    }
+  }
+}`)
+
+	code := s.Attributes["code"].Attribute.(tfschema.StringAttribute)
+	for value, wantErr := range map[string]bool{"AB": false, "ABCD": false, "A": true, "ABCDE": true, "ab": true} {
+		if diags := validateString(code.Validators, types.StringValue(value)); diags.HasError() != wantErr {
+			t.Errorf("code %q: got errors %v, want error %v", value, diags, wantErr)
+		}
+	}
+
+	// count is reserved by Terraform, so the attribute is count_.
+	if a := s.Attributes["count_"]; a == nil || a.JSONName != "count" {
+		t.Fatalf("count_ = %v, want the attribute of the count field", a)
+	}
+	count := s.Attributes["count_"].Attribute.(tfschema.Int64Attribute)
+	for value, wantErr := range map[int64]bool{1: false, 9: false, 0: true, 10: true} {
+		if diags := validateInt64(count.Validators, types.Int64Value(value)); diags.HasError() != wantErr {
+			t.Errorf("count %d: got errors %v, want error %v", value, diags, wantErr)
+		}
+	}
+
+	ratio := s.Attributes["ratio"].Attribute.(tfschema.NumberAttribute)
+	for value, wantErr := range map[float64]bool{0.5: false, 1: false, 0: true, 1.5: true} {
+		if diags := validateNumber(ratio.Validators, types.NumberValue(big.NewFloat(value))); diags.HasError() != wantErr {
+			t.Errorf("ratio %v: got errors %v, want error %v", value, diags, wantErr)
+		}
+	}
+
+	names := s.Attributes["names"].Attribute.(tfschema.ListAttribute)
+	for _, tc := range []struct {
+		value   []string
+		wantErr bool
+	}{
+		{[]string{"a", "bc"}, false},
+		{[]string{}, true},
+		{[]string{"a", "b", "c"}, true},
+		{[]string{"abcd"}, true},
+	} {
+		list, _ := types.ListValueFrom(context.TODO(), types.StringType, tc.value)
+		if diags := validateList(names.Validators, list); diags.HasError() != tc.wantErr {
+			t.Errorf("names %v: got errors %v, want error %v", tc.value, diags, tc.wantErr)
+		}
+	}
+
+	owner := s.Attributes["owner"].Attribute.(tfschema.SingleNestedAttribute)
+	email := owner.Attributes["email"].(tfschema.StringAttribute)
+	if diags := validateString(email.Validators, types.StringValue("ab")); !diags.HasError() {
+		t.Error("nested email accepted a value shorter than minLength")
+	}
+
+	// Items and map values that are references are validated against the
+	// schema they refer to.
+	codes := s.Attributes["codes"].Attribute.(tfschema.SetAttribute)
+	for value, wantErr := range map[string]bool{"AB": false, "A": true, "ABCDE": true, "ab": true} {
+		set, _ := types.SetValueFrom(context.TODO(), types.StringType, []string{value})
+		if diags := validateSet(codes.Validators, set); diags.HasError() != wantErr {
+			t.Errorf("codes [%q]: got errors %v, want error %v", value, diags, wantErr)
+		}
+	}
+	if diags := validateInt64Elements(t, s.Attributes["scores"].Attribute, []int64{1, 9}); diags.HasError() {
+		t.Errorf("scores [1 9]: got errors %v", diags)
+	}
+	if diags := validateInt64Elements(t, s.Attributes["scores"].Attribute, []int64{10}); !diags.HasError() {
+		t.Error("scores accepted a value past exclusiveMaximum")
+	}
+}
+
+func validateInt64(validators []validator.Int64, v types.Int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, val := range validators {
+		resp := &validator.Int64Response{}
+		val.ValidateInt64(context.TODO(), validator.Int64Request{ConfigValue: v}, resp)
+		diags.Append(resp.Diagnostics...)
+	}
+	return diags
+}
+
+func validateNumber(validators []validator.Number, v types.Number) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, val := range validators {
+		resp := &validator.NumberResponse{}
+		val.ValidateNumber(context.TODO(), validator.NumberRequest{ConfigValue: v}, resp)
+		diags.Append(resp.Diagnostics...)
+	}
+	return diags
+}
+
+func validateList(validators []validator.List, v types.List) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, val := range validators {
+		resp := &validator.ListResponse{}
+		val.ValidateList(context.TODO(), validator.ListRequest{ConfigValue: v}, resp)
+		diags.Append(resp.Diagnostics...)
+	}
+	return diags
+}
+
+func validateSet(validators []validator.Set, v types.Set) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, val := range validators {
+		resp := &validator.SetResponse{}
+		val.ValidateSet(context.TODO(), validator.SetRequest{ConfigValue: v}, resp)
+		diags.Append(resp.Diagnostics...)
+	}
+	return diags
+}
+
+func validateMap(validators []validator.Map, v types.Map) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, val := range validators {
+		resp := &validator.MapResponse{}
+		val.ValidateMap(context.TODO(), validator.MapRequest{ConfigValue: v}, resp)
+		diags.Append(resp.Diagnostics...)
+	}
+	return diags
+}
+
+func TestDefaults(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "required": ["color"],
+        "properties": {
+          "color": {"type": "string", "default": "RED"},
+          "count": {"type": "integer", "default": 3},
+          "ratio": {"type": "number", "default": 0.5},
+          "enabled": {"type": "boolean", "default": false},
+          "tags": {"type": "array", "items": {"type": "string"}, "default": ["a", "b"]},
+          "mismatched": {"type": "integer", "default": "three"}
+        }
+      }
+    }
+  }
+}`)
+
+	color := s.Attributes["color"].Attribute.(tfschema.StringAttribute)
+	if color.Required || !color.Optional || !color.Computed {
+		t.Errorf("color is Required=%v Optional=%v Computed=%v, want Optional and Computed", color.Required, color.Optional, color.Computed)
+	}
+	if want := "Defaults to `\"RED\"`."; color.MarkdownDescription != want {
+		t.Errorf("color description = %q, want %q", color.MarkdownDescription, want)
+	}
+	stringResp := &defaults.StringResponse{}
+	color.Default.DefaultString(context.TODO(), defaults.StringRequest{}, stringResp)
+	if got := stringResp.PlanValue; !got.Equal(types.StringValue("RED")) {
+		t.Errorf("color default = %v, want RED", got)
+	}
+
+	if a := s.Attributes["count_"]; a == nil || a.JSONName != "count" {
+		t.Fatalf("count_ = %v, want the attribute of the count field", a)
+	}
+	count := s.Attributes["count_"].Attribute.(tfschema.Int64Attribute)
+	int64Resp := &defaults.Int64Response{}
+	count.Default.DefaultInt64(context.TODO(), defaults.Int64Request{}, int64Resp)
+	if got := int64Resp.PlanValue; !got.Equal(types.Int64Value(3)) {
+		t.Errorf("count default = %v, want 3", got)
+	}
+
+	ratio := s.Attributes["ratio"].Attribute.(tfschema.NumberAttribute)
+	numberResp := &defaults.NumberResponse{}
+	ratio.Default.DefaultNumber(context.TODO(), defaults.NumberRequest{}, numberResp)
+	if got := numberResp.PlanValue; !got.Equal(types.NumberValue(big.NewFloat(0.5))) {
+		t.Errorf("ratio default = %v, want 0.5", got)
+	}
+
+	enabled := s.Attributes["enabled"].Attribute.(tfschema.BoolAttribute)
+	if !enabled.Computed || enabled.Default == nil {
+		t.Fatalf("enabled has Computed=%v Default=%v, want a default", enabled.Computed, enabled.Default)
+	}
+	boolResp := &defaults.BoolResponse{}
+	enabled.Default.DefaultBool(context.TODO(), defaults.BoolRequest{}, boolResp)
+	if got := boolResp.PlanValue; !got.Equal(types.BoolValue(false)) {
+		t.Errorf("enabled default = %v, want false", got)
+	}
+
+	tags := s.Attributes["tags"].Attribute.(tfschema.ListAttribute)
+	listResp := &defaults.ListResponse{}
+	tags.Default.DefaultList(context.TODO(), defaults.ListRequest{}, listResp)
+	want, _ := types.ListValueFrom(context.TODO(), types.StringType, []string{"a", "b"})
+	if got := listResp.PlanValue; !got.Equal(want) {
+		t.Errorf("tags default = %v, want %v", got, want)
+	}
+
+	// A default that doesn't match is dropped with a warning, so the
+	// attribute isn't unknown on every plan.
+	mismatched := s.Attributes["mismatched"].Attribute.(tfschema.Int64Attribute)
+	if mismatched.Default != nil || mismatched.Computed || !mismatched.Optional {
+		t.Errorf("mismatched has Computed=%v Optional=%v Default=%v, want Optional without a default", mismatched.Computed, mismatched.Optional, mismatched.Default)
+	}
+	// The other warning is about count being renamed to count_.
+	if len(s.Diagnostics) != 2 || !strings.Contains(s.Diagnostics[0].Detail(), `"mismatched" has the default "three"`) ||
+		!strings.Contains(s.Diagnostics[1].Detail(), `"count_"`) {
+		t.Errorf("diagnostics = %v, want a warning about mismatched", s.Diagnostics)
+	}
+}
+
+func TestDescriptions(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "title": "Thing",
+        "description": "A thing in a shelf.",
+        "x-aep-resource": {"singular": "thing", "description": "A thing."},
+        "properties": {
+          "color": {
+            "type": "string",
+            "title": "Color",
+            "description": "The color.",
+            "enum": ["RED", "GREEN"],
+            "example": "RED",
+            "x-aep-field-behavior": ["IMMUTABLE"]
+          },
+          "size": {"type": "integer", "title": "Size", "examples": [1, 2], "deprecated": true},
+          "label": {"type": "string", "title": "Label", "description": "Label of the thing."},
+          "spec": {"$ref": "#/components/schemas/spec", "deprecated": true, "example": {"a": 1}}
+        }
+      },
+      "spec": {
+        "type": "object",
+        "description": "The spec.",
+        "properties": {"a": {"type": "integer"}}
+      }
+    }
+  }
+}`)
+
+	if want := "**Thing**\n\nA thing."; s.Description != want {
+		t.Errorf("resource description = %q, want %q", s.Description, want)
+	}
+	if s.DeprecationMessage != "" {
+		t.Errorf("resource deprecation = %q, want none", s.DeprecationMessage)
+	}
+
+	color := s.Attributes["color"].Attribute.(tfschema.StringAttribute)
+	want := "**Color**\n\nThe color.\n\nField behavior: `IMMUTABLE`.\n\nMust be one of: `RED`, `GREEN`.\n\nExample: `\"RED\"`."
+	if color.MarkdownDescription != want {
+		t.Errorf("color description = %q, want %q", color.MarkdownDescription, want)
+	}
+	if color.DeprecationMessage != "" {
+		t.Errorf("color deprecation = %q, want none", color.DeprecationMessage)
+	}
+
+	size := s.Attributes["size"].Attribute.(tfschema.Int64Attribute)
+	if want := "**Size**\n\nExamples: `1`, `2`."; size.MarkdownDescription != want {
+		t.Errorf("size description = %q, want %q", size.MarkdownDescription, want)
+	}
+	if size.DeprecationMessage == "" {
+		t.Error("size isn't deprecated")
+	}
+
+	// A title that starts the description isn't repeated.
+	label := s.Attributes["label"].Attribute.(tfschema.StringAttribute)
+	if want := "Label of the thing."; label.MarkdownDescription != want {
+		t.Errorf("label description = %q, want %q", label.MarkdownDescription, want)
+	}
+
+	spec := s.Attributes["spec"].Attribute.(tfschema.SingleNestedAttribute)
+	if want := "The spec.\n\nExample: `{\"a\":1}`."; spec.MarkdownDescription != want {
+		t.Errorf("spec description = %q, want %q", spec.MarkdownDescription, want)
+	}
+	if spec.DeprecationMessage == "" {
+		t.Error("spec isn't deprecated")
+	}
+}
+
+func TestDeprecatedResource(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "deprecated": true,
+        "properties": {"name": {"type": "string"}}
+      }
+    }
+  }
+}`)
+
+	if s.Description != "thing" {
+		t.Errorf("resource description = %q, want thing", s.Description)
+	}
+	if s.DeprecationMessage == "" {
+		t.Error("resource isn't deprecated")
+	}
+}
+
+func TestSensitive(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "properties": {
+          "password": {"type": "string", "format": "password"},
+          "secret": {"type": "string", "writeOnly": true},
+          "token": {"type": "string", "x-aep-sensitive": true},
+          "settings": {"type": "object", "properties": {"key": {"type": "string", "writeOnly": true}}},
+          "name": {"type": "string"}
+        }
+      }
+    }
+  }
+}`)
+
+	for _, name := range []string{"password", "secret", "token"} {
+		if a := s.Attributes[name].Attribute.(tfschema.StringAttribute); !a.Sensitive {
+			t.Errorf("%s is not sensitive", name)
+		}
+		if a := s.Attributes[name].DatasourceAttribute.(dsschema.StringAttribute); !a.Sensitive {
+			t.Errorf("%s is not sensitive in the data source", name)
+		}
+	}
+	if a := s.Attributes["name"].Attribute.(tfschema.StringAttribute); a.Sensitive {
+		t.Error("name is sensitive")
+	}
+
+	if !s.Attributes["secret"].WriteOnly || s.Attributes["password"].WriteOnly {
+		t.Error("only secret should be write-only")
+	}
+	key := s.Attributes["settings"].NestedAttributes["key"]
+	if !key.WriteOnly || !key.Attribute.(tfschema.StringAttribute).Sensitive {
+		t.Error("nested key should be write-only and sensitive")
+	}
+}
+
+func TestImmutable(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "properties": {
+          "region": {"type": "string", "x-aep-field-behavior": ["IMMUTABLE"]},
+          "size": {"type": "integer", "default": 1, "x-aep-field-behavior": ["IMMUTABLE"]},
+          "zones": {"type": "array", "items": {"type": "string"}, "x-aep-field-behavior": ["IMMUTABLE"]},
+          "network": {"$ref": "#/components/schemas/network", "x-aep-field-behavior": ["IMMUTABLE"]},
+          "name": {"type": "string"}
+        }
+      },
+      "network": {
+        "type": "object",
+        "properties": {
+          "subnet": {"type": "string", "x-aep-field-behavior": ["IMMUTABLE"]},
+          "enabled": {"type": "boolean"}
+        }
+      }
+    }
+  }
+}`)
+
+	region := s.Attributes["region"].Attribute.(tfschema.StringAttribute)
+	if got := planModifierDescriptions(region.PlanModifiers); !cmp.Equal(got, []string{stringplanmodifier.RequiresReplace().Description(context.TODO())}) {
+		t.Errorf("region plan modifiers = %v, want RequiresReplace", got)
+	}
+
+	// size is computed because of its default, so the state is used for
+	// unknown values before the replacement is decided.
+	size := s.Attributes["size"].Attribute.(tfschema.Int64Attribute)
+	if got := planModifierDescriptions(size.PlanModifiers); len(got) != 2 {
+		t.Errorf("size plan modifiers = %v, want UseStateForUnknown and RequiresReplace", got)
+	}
+
+	if zones := s.Attributes["zones"].Attribute.(tfschema.ListAttribute); len(zones.PlanModifiers) != 1 {
+		t.Errorf("zones has %d plan modifiers, want 1", len(zones.PlanModifiers))
+	}
+
+	network := s.Attributes["network"].Attribute.(tfschema.SingleNestedAttribute)
+	if len(network.PlanModifiers) != 1 {
+		t.Errorf("network has %d plan modifiers, want 1", len(network.PlanModifiers))
+	}
+	if subnet := network.Attributes["subnet"].(tfschema.StringAttribute); len(subnet.PlanModifiers) != 1 {
+		t.Errorf("nested subnet has %d plan modifiers, want 1", len(subnet.PlanModifiers))
+	}
+	if enabled := network.Attributes["enabled"].(tfschema.BoolAttribute); len(enabled.PlanModifiers) != 0 {
+		t.Errorf("nested enabled has %d plan modifiers, want none", len(enabled.PlanModifiers))
+	}
+
+	if name := s.Attributes["name"].Attribute.(tfschema.StringAttribute); len(name.PlanModifiers) != 0 {
+		t.Errorf("name has %d plan modifiers, want none", len(name.PlanModifiers))
+	}
+}
+
+func planModifierDescriptions[T interface{ Description(context.Context) string }](modifiers []T) []string {
+	var descriptions []string
+	for _, m := range modifiers {
+		descriptions = append(descriptions, m.Description(context.TODO()))
+	}
+	return descriptions
+}
+
+func TestComposition(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "allOf": [
+          {"$ref": "#/components/schemas/base"},
+          {"properties": {"size": {"type": "integer"}}, "required": ["size"]}
+        ],
+        "properties": {
+          "target": {
+            "description": "Where to send events.",
+            "oneOf": [
+              {"$ref": "#/components/schemas/bucket"},
+              {"$ref": "#/components/schemas/topic"},
+              {"type": "string"}
+            ]
+          },
+          "labels": {"anyOf": [{"$ref": "#/components/schemas/labels"}, {"type": "null"}]}
+        }
+      },
+      "base": {
+        "type": "object",
+        "properties": {"name": {"type": "string"}},
+        "required": ["name"]
+      },
+      "bucket": {"type": "object", "properties": {"bucket": {"type": "string"}}},
+      "topic": {"type": "object", "properties": {"topic": {"type": "string"}, "region": {"type": "string"}}},
+      "labels": {"type": "object", "properties": {"env": {"type": "string"}}}
+    }
+  }
+}`)
+
+	for _, name := range []string{"name", "size"} {
+		a, ok := s.Attributes[name]
+		if !ok {
+			t.Fatalf("allOf attribute %s is missing", name)
+		}
+		if !a.Attribute.IsRequired() {
+			t.Errorf("%s is not required", name)
+		}
+	}
+
+	target := s.Attributes["target"]
+	if target.Type != ONE_OF {
+		t.Fatalf("target type = %s, want %s", target.Type, ONE_OF)
+	}
+	attribute := target.Attribute.(tfschema.SingleNestedAttribute)
+	var variants []string
+	for name, a := range attribute.Attributes {
+		variants = append(variants, name)
+		if !a.IsOptional() {
+			t.Errorf("variant %s is not optional", name)
+		}
+	}
+	sort.Strings(variants)
+	if want := []string{"bucket", "string", "topic"}; !cmp.Equal(variants, want) {
+		t.Errorf("target variants = %v, want %v", variants, want)
+	}
+	if want := "Where to send events.\n\nExactly one of `bucket`, `string`, `topic` must be set."; attribute.MarkdownDescription != want {
+		t.Errorf("target description = %q, want %q", attribute.MarkdownDescription, want)
+	}
+	if len(attribute.Validators) != 1 {
+		t.Errorf("target has %d validators, want 1", len(attribute.Validators))
+	}
+
+	// A null alternative only makes labels nullable.
+	if labels := s.Attributes["labels"]; labels.Type != OBJECT {
+		t.Errorf("labels type = %s, want %s", labels.Type, OBJECT)
+	}
+}
+
+func TestOneOfValidator(t *testing.T) {
+	variantType := map[string]attr.Type{"a": types.StringType, "b": types.StringType}
+	tests := []struct {
+		name    string
+		value   types.Object
+		wantErr bool
+	}{
+		{"one", types.ObjectValueMust(variantType, map[string]attr.Value{"a": types.StringValue("x"), "b": types.StringNull()}), false},
+		{"both", types.ObjectValueMust(variantType, map[string]attr.Value{"a": types.StringValue("x"), "b": types.StringValue("y")}), true},
+		{"none", types.ObjectValueMust(variantType, map[string]attr.Value{"a": types.StringNull(), "b": types.StringNull()}), true},
+		{"unknown", types.ObjectValueMust(variantType, map[string]attr.Value{"a": types.StringUnknown(), "b": types.StringNull()}), false},
+		{"null", types.ObjectNull(variantType), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &validator.ObjectResponse{}
+			oneOfValidator{variants: []string{"a", "b"}}.ValidateObject(context.TODO(), validator.ObjectRequest{ConfigValue: tt.value}, resp)
+			if got := resp.Diagnostics.HasError(); got != tt.wantErr {
+				t.Errorf("HasError() = %v, want %v: %v", got, tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestMapAttributes(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "properties": {
+          "labels": {"type": "object", "additionalProperties": {"type": "string", "maxLength": 63}},
+          "limits": {"type": "object", "additionalProperties": {"type": "integer"}},
+          "ports": {"type": "object", "additionalProperties": {"$ref": "#/components/schemas/port"}},
+          "metadata": {"type": "object", "additionalProperties": true},
+          "closed": {"type": "object", "additionalProperties": false}
+        }
+      },
+      "port": {"type": "object", "properties": {"number": {"type": "integer"}}}
+    }
+  }
+}`)
+
+	labels := s.Attributes["labels"]
+	if labels.Type != MAP || labels.ListItemType != STRING {
+		t.Errorf("labels is %s of %s, want map of string", labels.Type, labels.ListItemType)
+	}
+	labelsAttribute := labels.Attribute.(tfschema.MapAttribute)
+	if labelsAttribute.ElementType != types.StringType {
+		t.Errorf("labels element type = %v, want string", labelsAttribute.ElementType)
+	}
+	if len(labelsAttribute.Validators) != 1 {
+		t.Errorf("labels has %d validators, want 1", len(labelsAttribute.Validators))
+	}
+
+	if limits := s.Attributes["limits"].Attribute.(tfschema.MapAttribute); limits.ElementType != types.Int64Type {
+		t.Errorf("limits element type = %v, want int64", limits.ElementType)
+	}
+
+	ports := s.Attributes["ports"]
+	if ports.Type != MAP || ports.ListItemType != OBJECT {
+		t.Errorf("ports is %s of %s, want map of object", ports.Type, ports.ListItemType)
+	}
+	if _, ok := ports.Attribute.(tfschema.MapNestedAttribute).NestedObject.Attributes["number"]; !ok {
+		t.Error("ports values have no number attribute")
+	}
+
+	for _, name := range []string{"metadata", "closed"} {
+		if a := s.Attributes[name]; a.Type != JSON_OBJECT {
+			t.Errorf("%s type = %s, want %s", name, a.Type, JSON_OBJECT)
+		}
+	}
+}
+
+func TestArrayAttributes(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "properties": {
+          "rules": {"type": "array", "items": {"$ref": "#/components/schemas/rule"}},
+          "matrix": {"type": "array", "items": {"type": "array", "items": {"type": "integer"}}},
+          "groups": {"type": "array", "items": {"type": "array", "items": {"$ref": "#/components/schemas/rule"}}},
+          "extras": {"type": "array", "items": {"type": "object"}},
+          "values": {"type": "array", "items": {}}
+        }
+      },
+      "rule": {"type": "object", "properties": {"action": {"type": "string"}}}
+    }
+  }
+}`)
+
+	rules := s.Attributes["rules"]
+	if rules.ListItemType != OBJECT {
+		t.Errorf("rules items are %s, want %s", rules.ListItemType, OBJECT)
+	}
+	if _, ok := rules.Attribute.(tfschema.ListNestedAttribute).NestedObject.Attributes["action"]; !ok {
+		t.Error("rules items have no action attribute")
+	}
+
+	matrix := s.Attributes["matrix"].Attribute.(tfschema.ListAttribute)
+	if want := (types.ListType{ElemType: types.Int64Type}); !matrix.ElementType.Equal(want) {
+		t.Errorf("matrix element type = %v, want %v", matrix.ElementType, want)
+	}
+
+	groups := s.Attributes["groups"]
+	wantGroups := types.ListType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{"action": types.StringType}}}
+	if got := groups.Attribute.(tfschema.ListAttribute).ElementType; !got.Equal(wantGroups) {
+		t.Errorf("groups element type = %v, want %v", got, wantGroups)
+	}
+	if inner := groups.ItemAttribute.ItemAttribute; inner == nil || inner.Type != OBJECT || inner.NestedAttributes["action"] == nil {
+		t.Errorf("groups inner items = %+v, want objects with an action", inner)
+	}
+
+	for _, name := range []string{"extras", "values"} {
+		a := s.Attributes[name]
+		if a.ListItemType != JSON_OBJECT {
+			t.Errorf("%s items are %s, want %s", name, a.ListItemType, JSON_OBJECT)
+		}
+		if got := a.Attribute.(tfschema.ListAttribute).ElementType; !got.Equal(jsontypes.NormalizedType{}) {
+			t.Errorf("%s element type = %v, want normalized JSON", name, got)
+		}
+	}
+}
+
+func TestSetAttributes(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "properties": {
+          "tags": {"type": "array", "uniqueItems": true, "maxItems": 10, "items": {"type": "string"}, "default": ["a"]},
+          "members": {"type": "array", "uniqueItems": true, "items": {"$ref": "#/components/schemas/member"}},
+          "zones": {"type": "array", "items": {"type": "array", "uniqueItems": true, "items": {"type": "string"}}}
+        }
+      },
+      "member": {"type": "object", "properties": {"email": {"type": "string"}}}
+    }
+  }
+}`)
+
+	tags := s.Attributes["tags"]
+	if tags.Type != SET {
+		t.Errorf("tags type = %s, want %s", tags.Type, SET)
+	}
+	tagsAttribute := tags.Attribute.(tfschema.SetAttribute)
+	if tagsAttribute.ElementType != types.StringType {
+		t.Errorf("tags element type = %v, want string", tagsAttribute.ElementType)
+	}
+	if len(tagsAttribute.Validators) != 1 || tagsAttribute.Default == nil {
+		t.Errorf("tags has %d validators and default %v, want 1 validator and a default", len(tagsAttribute.Validators), tagsAttribute.Default)
+	}
+
+	members := s.Attributes["members"]
+	if members.Type != SET || members.ListItemType != OBJECT {
+		t.Errorf("members is %s of %s, want set of object", members.Type, members.ListItemType)
+	}
+	if _, ok := members.Attribute.(tfschema.SetNestedAttribute).NestedObject.Attributes["email"]; !ok {
+		t.Error("members have no email attribute")
+	}
+
+	zones := s.Attributes["zones"].Attribute.(tfschema.ListAttribute)
+	if want := (types.SetType{ElemType: types.StringType}); !zones.ElementType.Equal(want) {
+		t.Errorf("zones element type = %v, want %v", zones.ElementType, want)
+	}
+}
+
+func TestFormats(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "properties": {
+          "expire_time": {"type": "string", "format": "date-time"},
+          "ttl": {"type": "string", "format": "duration"},
+          "homepage": {"type": "string", "format": "uri"},
+          "owner": {"type": "string", "format": "email"},
+          "address": {"type": "string", "format": "ipv4"},
+          "address_v6": {"type": "string", "format": "ipv6"},
+          "uid": {"type": "string", "format": "uuid"},
+          "windows": {"type": "array", "items": {"type": "string", "format": "date-time"}}
+        }
+      }
+    }
+  }
+}`)
+
+	expireTime := s.Attributes["expire_time"].Attribute.(tfschema.StringAttribute)
+	if expireTime.CustomType != (timetypes.RFC3339Type{}) {
+		t.Errorf("expire_time custom type = %v, want RFC3339", expireTime.CustomType)
+	}
+	if windows := s.Attributes["windows"].Attribute.(tfschema.ListAttribute); windows.ElementType != (timetypes.RFC3339Type{}) {
+		t.Errorf("windows element type = %v, want RFC3339", windows.ElementType)
+	}
+
+	tests := []struct {
+		attribute string
+		valid     []string
+		invalid   []string
+	}{
+		{"ttl", []string{"3.5s", "1h30m", "P1DT12H", "PT0.5S"}, []string{"5", "P", "PT", "1 day"}},
+		{"homepage", []string{"https://example.com/a?b=c", "urn:isbn:0451450523"}, []string{"example.com", "/relative"}},
+		{"owner", []string{"jane@example.com"}, []string{"jane", "Jane <jane@example.com>"}},
+		{"address", []string{"10.0.0.1"}, []string{"::1", "10.0.0.256"}},
+		{"address_v6", []string{"::1", "2001:db8::8a2e:370:7334"}, []string{"10.0.0.1", "2001:db8::g"}},
+		{"uid", []string{"123e4567-e89b-12d3-a456-426614174000"}, []string{"123e4567e89b12d3a456426614174000"}},
+	}
+	for _, tt := range tests {
+		validators := s.Attributes[tt.attribute].Attribute.(tfschema.StringAttribute).Validators
+		for _, v := range tt.valid {
+			if diags := validateString(validators, types.StringValue(v)); diags.HasError() {
+				t.Errorf("%s %q is invalid: %v", tt.attribute, v, diags)
+			}
+		}
+		for _, v := range tt.invalid {
+			if diags := validateString(validators, types.StringValue(v)); !diags.HasError() {
+				t.Errorf("%s %q is valid", tt.attribute, v)
+			}
+		}
+	}
+}
+
+func TestInt64Strings(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "properties": {
+          "size": {"type": "string", "format": "int64", "default": "5", "enum": ["5", "9007199254740993"]},
+          "sizes": {"type": "array", "items": {"type": "string", "format": "int64"}},
+          "count64": {"type": "integer", "format": "int64"}
+        }
+      }
+    }
+  }
+}`)
+
+	size := s.Attributes["size"]
+	if size.Type != INTEGER || !size.StringEncoded {
+		t.Fatalf("size is %s with StringEncoded=%v, want a string encoded %s", size.Type, size.StringEncoded, INTEGER)
+	}
+	sizeAttr := size.Attribute.(tfschema.Int64Attribute)
+	int64Resp := &defaults.Int64Response{}
+	sizeAttr.Default.DefaultInt64(context.TODO(), defaults.Int64Request{}, int64Resp)
+	if got := int64Resp.PlanValue; !got.Equal(types.Int64Value(5)) {
+		t.Errorf("size default = %v, want 5", got)
+	}
+	if diags := validateInt64(sizeAttr.Validators, types.Int64Value(9007199254740993)); diags.HasError() {
+		t.Errorf("size rejected a value in the enum: %v", diags)
+	}
+	if diags := validateInt64(sizeAttr.Validators, types.Int64Value(6)); !diags.HasError() {
+		t.Error("size accepted a value outside the enum")
+	}
+
+	sizes := s.Attributes["sizes"]
+	if got := sizes.Attribute.(tfschema.ListAttribute).ElementType; !got.Equal(types.Int64Type) {
+		t.Errorf("sizes element type = %v, want %v", got, types.Int64Type)
+	}
+	list := []Value{{Number: new(big.Float).SetInt64(9007199254740993)}}
+	got, err := ConvertValue(Value{List: &list}, sizes)
+	if err != nil {
+		t.Fatalf("ConvertValue() error = %v", err)
+	}
+	if want := []interface{}{"9007199254740993"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ConvertValue() = %#v, want %#v", got, want)
+	}
+
+	// Integers are only strings when the spec says so.
+	if count := s.Attributes["count64"]; count.Type != INTEGER || count.StringEncoded {
+		t.Errorf("count64 is %s with StringEncoded=%v, want a plain %s", count.Type, count.StringEncoded, INTEGER)
+	}
+}
+
+func TestInt64StringItemValidators(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "properties": {
+          "list": {"type": "array", "items": {"type": "string", "format": "int64", "enum": ["1", "9007199254740993"]}},
+          "set": {"type": "array", "uniqueItems": true, "items": {"type": "string", "format": "int64", "enum": ["1", "9007199254740993"]}},
+          "map": {"type": "object", "additionalProperties": {"type": "string", "format": "int64", "enum": ["1", "9007199254740993"]}},
+          "pattern_list": {"type": "array", "items": {"type": "string", "format": "int64", "pattern": "^[0-9]+$"}},
+          "pattern_set": {"type": "array", "uniqueItems": true, "items": {"type": "string", "format": "int64", "pattern": "^[0-9]+$"}},
+          "pattern_map": {"type": "object", "additionalProperties": {"type": "string", "format": "int64", "pattern": "^[0-9]+$"}}
+        }
+      }
+    }
+  }
+}`)
+
+	testCases := []struct {
+		name    string
+		values  []int64
+		wantErr bool
+	}{
+		{"list", []int64{1, 9007199254740993}, false},
+		{"list", []int64{2}, true},
+		{"set", []int64{1, 9007199254740993}, false},
+		{"set", []int64{2}, true},
+		{"map", []int64{1, 9007199254740993}, false},
+		{"map", []int64{2}, true},
+		// The pattern applies to the JSON string, which is always a valid
+		// integer, so it is left out.
+		{"pattern_list", []int64{5}, false},
+		{"pattern_set", []int64{5}, false},
+		{"pattern_map", []int64{5}, false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			diags := validateInt64Elements(t, s.Attributes[testCase.name].Attribute, testCase.values)
+			if diags.HasError() != testCase.wantErr {
+				t.Errorf("%v: got errors %v, want error %v", testCase.values, diags, testCase.wantErr)
 			}
 		})
 	}
 }
+
+// validateInt64Elements runs the validators of the list, set or map attribute
+// a on a collection of values.
+func validateInt64Elements(t *testing.T, a tfschema.Attribute, values []int64) diag.Diagnostics {
+	t.Helper()
+	elements := make([]attr.Value, len(values))
+	mapElements := make(map[string]attr.Value, len(values))
+	for i, v := range values {
+		elements[i] = types.Int64Value(v)
+		mapElements[strconv.Itoa(i)] = types.Int64Value(v)
+	}
+	var diags diag.Diagnostics
+	switch a := a.(type) {
+	case tfschema.ListAttribute:
+		diags.Append(validateList(a.Validators, types.ListValueMust(types.Int64Type, elements))...)
+	case tfschema.SetAttribute:
+		for _, val := range a.Validators {
+			resp := &validator.SetResponse{}
+			val.ValidateSet(context.TODO(), validator.SetRequest{ConfigValue: types.SetValueMust(types.Int64Type, elements)}, resp)
+			diags.Append(resp.Diagnostics...)
+		}
+	case tfschema.MapAttribute:
+		for _, val := range a.Validators {
+			resp := &validator.MapResponse{}
+			val.ValidateMap(context.TODO(), validator.MapRequest{ConfigValue: types.MapValueMust(types.Int64Type, mapElements)}, resp)
+			diags.Append(resp.Diagnostics...)
+		}
+	default:
+		t.Fatalf("unexpected attribute %T", a)
+	}
+	return diags
+}
+
+func TestJSONAttributes(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "properties": {
+          "metadata": {"type": "object"},
+          "value": {"$ref": "#/components/schemas/GoogleProtobufValue"}
+        }
+      },
+      "GoogleProtobufValue": {"description": "Any JSON value."}
+    }
+  }
+}`)
+
+	ctx := context.TODO()
+	for _, name := range []string{"metadata", "value"} {
+		a := s.Attributes[name]
+		if a.Type != JSON_OBJECT {
+			t.Errorf("%s type = %s, want %s", name, a.Type, JSON_OBJECT)
+		}
+		if got := a.Attribute.(tfschema.StringAttribute).CustomType; got != (jsontypes.NormalizedType{}) {
+			t.Errorf("%s custom type = %v, want normalized JSON", name, got)
+		}
+		if got := a.DatasourceAttribute.(dsschema.StringAttribute).CustomType; got != (jsontypes.NormalizedType{}) {
+			t.Errorf("%s data source custom type = %v, want normalized JSON", name, got)
+		}
+	}
+
+	// Reordered keys and whitespace don't make a difference.
+	equal, diags := jsontypes.NewNormalizedValue(`{"a": 1, "b": [true]}`).StringSemanticEquals(ctx, jsontypes.NewNormalizedValue(`{"b":[true],"a":1}`))
+	if diags.HasError() || !equal {
+		t.Errorf("reordered documents are not equal: %v", diags)
+	}
+
+	// Invalid JSON is reported against the attribute at validate time.
+	req := xattr.ValidateAttributeRequest{Path: path.Root("metadata")}
+	resp := &xattr.ValidateAttributeResponse{}
+	jsontypes.NewNormalizedValue(`{"a": `).ValidateAttribute(ctx, req, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("invalid JSON is valid")
+	}
+	if d, ok := resp.Diagnostics[0].(diag.DiagnosticWithPath); !ok || !d.Path().Equal(req.Path) {
+		t.Errorf("diagnostic %v is not reported at %s", resp.Diagnostics[0], req.Path)
+	}
+
+	// google.protobuf.Value is sent as the JSON it holds.
+	str := `{"enabled": true}`
+	got, err := ConvertValue(Value{String: &str}, s.Attributes["value"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]interface{}{"enabled": true}, got); diff != "" {
+		t.Errorf("ConvertValue() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDynamicAttributes(t *testing.T) {
+	s := newTestResourceSchema(t, `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "type": "object",
+        "x-aep-resource": {"singular": "thing"},
+        "properties": {
+          "metadata": {"type": "object"},
+          "value": {"$ref": "#/components/schemas/GoogleProtobufValue"},
+          "rules": {"type": "array", "items": {"type": "object", "properties": {"match": {"type": "object"}}}},
+          "child": {"$ref": "#/components/schemas/thing"}
+        }
+      },
+      "GoogleProtobufValue": {"description": "Any JSON value."}
+    }
+  }
+}`, func(_ *api.Resource, spec *Spec) {
+		spec.DynamicValues = true
+	})
+
+	for _, name := range []string{"metadata", "value"} {
+		a := s.Attributes[name]
+		if a.Type != DYNAMIC {
+			t.Errorf("%s type = %s, want %s", name, a.Type, DYNAMIC)
+		}
+		if _, ok := a.Attribute.(tfschema.DynamicAttribute); !ok {
+			t.Errorf("%s attribute is a %T, want a dynamic attribute", name, a.Attribute)
+		}
+		// Data sources list resources, and lists can't hold dynamic values.
+		if _, ok := a.DatasourceAttribute.(dsschema.StringAttribute); !ok {
+			t.Errorf("%s data source attribute is a %T, want a string", name, a.DatasourceAttribute)
+		}
+	}
+
+	// Neither can the items of a list.
+	if match := s.Attributes["rules"].NestedAttributes["match"]; match.Type != JSON_OBJECT {
+		t.Errorf("rules.match type = %s, want %s", match.Type, JSON_OBJECT)
+	}
+	// Recursive fields past the recursion depth stay JSON strings.
+	if parent := s.Attributes["child"].NestedAttributes["child"]; parent.Type != JSON_OBJECT {
+		t.Errorf("child.child type = %s, want %s", parent.Type, JSON_OBJECT)
+	}
+
+	// The framework accepts where the dynamic attributes are.
+	if diags := (tfschema.Schema{Attributes: s.FullSchema()}).ValidateImplementation(context.TODO()); diags.HasError() {
+		t.Errorf("resource schema is invalid: %v", diags)
+	}
+	if diags := (dsschema.Schema{Attributes: s.FullCollectionDataSourceSchema(context.TODO())}).ValidateImplementation(context.TODO()); diags.HasError() {
+		t.Errorf("data source schema is invalid: %v", diags)
+	}
+}
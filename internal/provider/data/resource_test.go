@@ -210,7 +210,35 @@ func TestToJSON(t *testing.T) {
 			},
 			expected: map[string]interface{}{
 				"foo@": "bar",
-				"qux":  "123",
+				"qux":  json.Number("123"),
+			},
+		},
+		{
+			name: "integers",
+			resource: Resource{
+				Values: map[string]Value{
+					"size": {Number: new(big.Float).SetInt64(9007199254740993)},
+					"id":   {Number: new(big.Float).SetInt64(-9007199254740993)},
+				},
+				Schema: &ResourceSchema{
+					Attributes: map[string]*ResourceAttribute{
+						"size": {
+							TerraformName: "size",
+							JSONName:      "size",
+							Type:          INTEGER,
+						},
+						"id": {
+							TerraformName: "id",
+							JSONName:      "id",
+							Type:          INTEGER,
+							StringEncoded: true,
+						},
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"size": json.Number("9007199254740993"),
+				"id":   "-9007199254740993",
 			},
 		},
 	}
@@ -227,3 +255,473 @@ func TestToJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestFromJSONWriteOnly(t *testing.T) {
+	schema := &ResourceSchema{
+		Attributes: map[string]*ResourceAttribute{
+			"name": {
+				TerraformName: "name",
+				JSONName:      "name",
+				Type:          STRING,
+			},
+			"password": {
+				TerraformName: "password",
+				JSONName:      "password",
+				Type:          STRING,
+				WriteOnly:     true,
+			},
+			"auth": {
+				TerraformName: "auth",
+				JSONName:      "auth",
+				Type:          OBJECT,
+				NestedAttributes: map[string]*ResourceAttribute{
+					"user": {
+						TerraformName: "user",
+						JSONName:      "user",
+						Type:          STRING,
+					},
+					"api_key": {
+						TerraformName: "api_key",
+						JSONName:      "apiKey",
+						Type:          STRING,
+						WriteOnly:     true,
+					},
+				},
+			},
+		},
+	}
+	plan := &Resource{
+		Schema: schema,
+		Values: map[string]Value{
+			"name":     {String: String("old")},
+			"password": {String: String("hunter2")},
+			"auth": {Object: &map[string]Value{
+				"user":    {String: String("admin")},
+				"api_key": {String: String("secret")},
+			}},
+		},
+	}
+	result := &Resource{Schema: schema, Values: map[string]Value{}}
+
+	// The server leaves out the top-level write-only field and returns an
+	// empty value for the nested one.
+	response := map[string]interface{}{
+		"name": "new",
+		"auth": map[string]interface{}{"user": "admin", "apiKey": nil},
+	}
+	if err := FromJSON(response, result, plan); err != nil {
+		t.Fatalf("FromJSON() error = %v", err)
+	}
+
+	expected := Resource{
+		Schema: schema,
+		Values: map[string]Value{
+			"name":     {String: String("new")},
+			"password": {String: String("hunter2")},
+			"auth": {Object: &map[string]Value{
+				"user":    {String: String("admin")},
+				"api_key": {String: String("secret")},
+			}},
+		},
+	}
+	checkResourceEqual(t, expected, *result)
+}
+
+func TestOneOf(t *testing.T) {
+	target := &ResourceAttribute{
+		TerraformName: "target",
+		JSONName:      "target",
+		Type:          ONE_OF,
+		NestedAttributes: map[string]*ResourceAttribute{
+			"bucket": {
+				TerraformName: "bucket",
+				JSONName:      "bucket",
+				Type:          OBJECT,
+				NestedAttributes: map[string]*ResourceAttribute{
+					"name": {TerraformName: "name", JSONName: "name", Type: STRING},
+				},
+			},
+			"topic": {
+				TerraformName: "topic",
+				JSONName:      "topic",
+				Type:          OBJECT,
+				NestedAttributes: map[string]*ResourceAttribute{
+					"name":   {TerraformName: "name", JSONName: "name", Type: STRING},
+					"region": {TerraformName: "region", JSONName: "region", Type: STRING},
+				},
+			},
+			"string": {TerraformName: "string", JSONName: "string", Type: STRING},
+		},
+	}
+
+	topic := Value{Object: &map[string]Value{
+		"topic": {Object: &map[string]Value{
+			"name":   {String: String("events")},
+			"region": {String: String("us")},
+		}},
+	}}
+	encoded, err := ConvertValue(topic, target)
+	if err != nil {
+		t.Fatalf("ConvertValue() error = %v", err)
+	}
+	want := map[string]interface{}{"name": "events", "region": "us"}
+	if !reflect.DeepEqual(encoded, want) {
+		t.Errorf("ConvertValue() = %v, want %v", encoded, want)
+	}
+
+	tests := []struct {
+		name     string
+		json     interface{}
+		plan     Value
+		expected Value
+	}{
+		{
+			name:     "object fields",
+			json:     map[string]interface{}{"name": "events", "region": "us"},
+			expected: topic,
+		},
+		{
+			name: "plan",
+			json: map[string]interface{}{"name": "events"},
+			plan: Value{Object: &map[string]Value{"topic": {Object: &map[string]Value{"name": {String: String("events")}}}}},
+			expected: Value{Object: &map[string]Value{
+				"topic": {Object: &map[string]Value{"name": {String: String("events")}}},
+			}},
+		},
+		{
+			name:     "string",
+			json:     "events",
+			expected: Value{Object: &map[string]Value{"string": {String: String("events")}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ConvertTypeToValue(tt.json, target, tt.plan)
+			if err != nil {
+				t.Fatalf("ConvertTypeToValue() error = %v", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("ConvertTypeToValue() = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+
+	if _, err := ConvertTypeToValue(true, target, Value{}); err == nil {
+		t.Error("ConvertTypeToValue() of a boolean should match no variant")
+	}
+	both := Value{Object: &map[string]Value{"string": {String: String("a")}, "topic": topic}}
+	if _, err := ConvertValue(both, target); err == nil {
+		t.Error("ConvertValue() with two variants set should fail")
+	}
+}
+
+func TestMap(t *testing.T) {
+	ports := &ResourceAttribute{
+		TerraformName: "ports",
+		JSONName:      "ports",
+		Type:          MAP,
+		ListItemType:  OBJECT,
+		NestedAttributes: map[string]*ResourceAttribute{
+			"name": {TerraformName: "name", JSONName: "name", Type: STRING},
+		},
+	}
+	labels := &ResourceAttribute{
+		TerraformName: "labels",
+		JSONName:      "labels",
+		Type:          MAP,
+		ListItemType:  STRING,
+	}
+
+	tests := []struct {
+		name      string
+		attribute *ResourceAttribute
+		json      interface{}
+		expected  Value
+	}{
+		{
+			name:      "strings",
+			attribute: labels,
+			json:      map[string]interface{}{"env": "prod", "team": "web"},
+			expected: Value{Map: &map[string]Value{
+				"env":  {String: String("prod")},
+				"team": {String: String("web")},
+			}},
+		},
+		{
+			name:      "objects",
+			attribute: ports,
+			json:      map[string]interface{}{"http": map[string]interface{}{"name": "web"}},
+			expected: Value{Map: &map[string]Value{
+				"http": {Object: &map[string]Value{"name": {String: String("web")}}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ConvertTypeToValue(tt.json, tt.attribute, Value{})
+			if err != nil {
+				t.Fatalf("ConvertTypeToValue() error = %v", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("ConvertTypeToValue() = %v, want %v", actual, tt.expected)
+			}
+			encoded, err := ConvertValue(actual, tt.attribute)
+			if err != nil {
+				t.Fatalf("ConvertValue() error = %v", err)
+			}
+			if !reflect.DeepEqual(encoded, tt.json) {
+				t.Errorf("ConvertValue() = %v, want %v", encoded, tt.json)
+			}
+		})
+	}
+}
+
+func TestNestedArrays(t *testing.T) {
+	rule := &ResourceAttribute{
+		Type: OBJECT,
+		NestedAttributes: map[string]*ResourceAttribute{
+			"action": {TerraformName: "action", JSONName: "action", Type: STRING},
+		},
+	}
+	tests := []struct {
+		name      string
+		attribute *ResourceAttribute
+		json      interface{}
+		expected  Value
+	}{
+		{
+			name: "list of lists of objects",
+			attribute: &ResourceAttribute{
+				Type:          ARRAY,
+				ListItemType:  ARRAY,
+				ItemAttribute: &ResourceAttribute{Type: ARRAY, ListItemType: OBJECT, ItemAttribute: rule},
+			},
+			json: []interface{}{[]interface{}{map[string]interface{}{"action": "allow"}}},
+			expected: Value{List: &[]Value{
+				{List: &[]Value{
+					{Object: &map[string]Value{"action": {String: String("allow")}}},
+				}},
+			}},
+		},
+		{
+			name: "list of JSON",
+			attribute: &ResourceAttribute{
+				Type:          ARRAY,
+				ListItemType:  JSON_OBJECT,
+				ItemAttribute: &ResourceAttribute{Type: JSON_OBJECT},
+			},
+			json: []interface{}{map[string]interface{}{"a": "b"}, "c"},
+			expected: Value{List: &[]Value{
+				{String: String(`{"a":"b"}`)},
+				{String: String(`"c"`)},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ConvertTypeToValue(tt.json, tt.attribute, Value{})
+			if err != nil {
+				t.Fatalf("ConvertTypeToValue() error = %v", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("ConvertTypeToValue() = %v, want %v", actual, tt.expected)
+			}
+			encoded, err := ConvertValue(actual, tt.attribute)
+			if err != nil {
+				t.Fatalf("ConvertValue() error = %v", err)
+			}
+			if !reflect.DeepEqual(encoded, tt.json) {
+				t.Errorf("ConvertValue() = %v, want %v", encoded, tt.json)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	tags := &ResourceAttribute{
+		TerraformName: "tags",
+		JSONName:      "tags",
+		Type:          SET,
+		ListItemType:  STRING,
+	}
+	response := []interface{}{"b", "a"}
+	actual, err := ConvertTypeToValue(response, tags, Value{})
+	if err != nil {
+		t.Fatalf("ConvertTypeToValue() error = %v", err)
+	}
+	expected := Value{Set: &[]Value{{String: String("b")}, {String: String("a")}}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ConvertTypeToValue() = %v, want %v", actual, expected)
+	}
+
+	v, err := ToTerraform5Value(actual, tftypes.Set{ElementType: tftypes.String})
+	if err != nil {
+		t.Fatalf("ToTerraform5Value() error = %v", err)
+	}
+	reordered := tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "a"),
+		tftypes.NewValue(tftypes.String, "b"),
+	})
+	if !v.Equal(reordered) {
+		t.Errorf("ToTerraform5Value() = %v, want %v regardless of order", v, reordered)
+	}
+
+	encoded, err := ConvertValue(actual, tags)
+	if err != nil {
+		t.Fatalf("ConvertValue() error = %v", err)
+	}
+	if !reflect.DeepEqual(encoded, response) {
+		t.Errorf("ConvertValue() = %v, want %v", encoded, response)
+	}
+}
+
+func TestDynamic(t *testing.T) {
+	settings := &ResourceAttribute{
+		TerraformName: "settings",
+		JSONName:      "settings",
+		Type:          DYNAMIC,
+	}
+	response := map[string]interface{}{
+		"enabled": true,
+		"ports":   []interface{}{float64(80), "http"},
+		"owner":   nil,
+	}
+	actual, err := ConvertTypeToValue(response, settings, Value{})
+	if err != nil {
+		t.Fatalf("ConvertTypeToValue() error = %v", err)
+	}
+
+	v, err := ToTerraform5Value(actual, tftypes.DynamicPseudoType)
+	if err != nil {
+		t.Fatalf("ToTerraform5Value() error = %v", err)
+	}
+	// The value has the type an HCL literal would have.
+	expected := tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"enabled": tftypes.Bool,
+		"ports":   tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.Number, tftypes.String}},
+		"owner":   tftypes.DynamicPseudoType,
+	}}, map[string]tftypes.Value{
+		"enabled": tftypes.NewValue(tftypes.Bool, true),
+		"ports": tftypes.NewValue(tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.Number, tftypes.String}}, []tftypes.Value{
+			tftypes.NewValue(tftypes.Number, big.NewFloat(80)),
+			tftypes.NewValue(tftypes.String, "http"),
+		}),
+		"owner": tftypes.NewValue(tftypes.DynamicPseudoType, nil),
+	})
+	if !v.Equal(expected) {
+		t.Errorf("ToTerraform5Value() = %v, want %v", v, expected)
+	}
+
+	// Terraform hands the value back with its own type.
+	roundTrip, err := FromTerraform5Value(v)
+	if err != nil {
+		t.Fatalf("FromTerraform5Value() error = %v", err)
+	}
+	encoded, err := ConvertValue(roundTrip, settings)
+	if err != nil {
+		t.Fatalf("ConvertValue() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"enabled": true,
+		"ports":   []interface{}{json.Number("80"), "http"},
+	}
+	if !reflect.DeepEqual(encoded, want) {
+		t.Errorf("ConvertValue() = %v, want %v", encoded, want)
+	}
+
+	// Data sources hold the value as a JSON string.
+	s, err := ToTerraform5Value(actual, tftypes.String)
+	if err != nil {
+		t.Fatalf("ToTerraform5Value() error = %v", err)
+	}
+	var str string
+	if err := s.As(&str); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"enabled":true,"owner":null,"ports":[80,"http"]}`; str != want {
+		t.Errorf("ToTerraform5Value() = %s, want %s", str, want)
+	}
+}
+
+func TestNumbers(t *testing.T) {
+	integer := &ResourceAttribute{Type: INTEGER}
+	number := &ResourceAttribute{Type: NUMBER}
+
+	testCases := []struct {
+		name string
+		v    interface{}
+		a    *ResourceAttribute
+		want string
+	}{
+		{"int64", json.Number("9223372036854775807"), integer, "9223372036854775807"},
+		{"past float64", json.Number("9007199254740993"), integer, "9007199254740993"},
+		{"int64 string", "-9007199254740993", integer, "-9007199254740993"},
+		{"float64", float64(42), integer, "42"},
+		{"number", json.Number("0.1"), number, "0.1"},
+		{"large number", json.Number("123456789012345678901234567890"), number, "123456789012345678901234567890"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			v, err := ConvertTypeToValue(testCase.v, testCase.a, Value{})
+			if err != nil {
+				t.Fatalf("ConvertTypeToValue() error = %v", err)
+			}
+			want, _, _ := big.ParseFloat(testCase.want, 10, 512, big.ToNearestEven)
+			if v.Number == nil || v.Number.Cmp(want) != 0 {
+				t.Errorf("ConvertTypeToValue() = %v, want %s", v.Number, testCase.want)
+			}
+
+			// The number survives the trip through Terraform.
+			raw, err := ToTerraform5Value(v, tftypes.Number)
+			if err != nil {
+				t.Fatalf("ToTerraform5Value() error = %v", err)
+			}
+			back, err := FromTerraform5Value(raw)
+			if err != nil {
+				t.Fatalf("FromTerraform5Value() error = %v", err)
+			}
+			sent, err := ConvertValue(back, testCase.a)
+			if err != nil {
+				t.Fatalf("ConvertValue() error = %v", err)
+			}
+			if got, ok := sent.(json.Number); !ok || got.String() != testCase.want {
+				t.Errorf("ConvertValue() = %#v, want %s", sent, testCase.want)
+			}
+		})
+	}
+
+	for name, v := range map[string]interface{}{
+		"fraction":       json.Number("1.5"),
+		"fraction float": 1.5,
+		"too large":      json.Number("9223372036854775808"),
+		"not a number":   "abc",
+		"boolean":        true,
+		"number string":  "1",
+	} {
+		a := integer
+		if name == "number string" {
+			a = number
+		}
+		if _, err := ConvertTypeToValue(v, a, Value{}); err == nil {
+			t.Errorf("ConvertTypeToValue(%v) accepted %s", v, name)
+		}
+	}
+
+	if _, err := ConvertValue(Value{Number: big.NewFloat(1.5)}, integer); err == nil {
+		t.Error("ConvertValue() sent 1.5 as an integer")
+	}
+
+	// Numbers in JSON strings are sent as they were written.
+	object := &ResourceAttribute{Type: JSON_OBJECT}
+	sent, err := ConvertValue(Value{String: String(`{"size": 9007199254740993, "ratio": 0.1}`)}, object)
+	if err != nil {
+		t.Fatalf("ConvertValue() error = %v", err)
+	}
+	want := map[string]interface{}{"size": json.Number("9007199254740993"), "ratio": json.Number("0.1")}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("ConvertValue() = %#v, want %#v", sent, want)
+	}
+	if _, err := ConvertValue(Value{String: String(`{} {}`)}, object); err == nil {
+		t.Error("ConvertValue() accepted two JSON values")
+	}
+}
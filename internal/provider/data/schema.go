@@ -0,0 +1,421 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aep-dev/aep-lib-go/pkg/api"
+	"github.com/aep-dev/aep-lib-go/pkg/openapi"
+	"gopkg.in/yaml.v3"
+)
+
+const componentSchemaPrefix = "#/components/schemas/"
+
+// Schema is an OpenAPI schema.
+//
+// It mirrors openapi.Schema, plus the JSON Schema keywords that the generated
+// Terraform schema depends on but openapi.Schema doesn't carry.
+type Schema struct {
+	Type             string            `json:"type,omitempty"`
+	Format           string            `json:"format,omitempty"`
+	Items            *Schema           `json:"items,omitempty"`
+	Properties       map[string]Schema `json:"properties,omitempty"`
+	Ref              string            `json:"$ref,omitempty"`
+	AllOf            []Schema          `json:"allOf,omitempty"`
+	OneOf            []Schema          `json:"oneOf,omitempty"`
+	AnyOf            []Schema          `json:"anyOf,omitempty"`
+	ReadOnly         bool              `json:"readOnly,omitempty"`
+	WriteOnly        bool              `json:"writeOnly,omitempty"`
+	Required         []string          `json:"required,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	Title            string            `json:"title,omitempty"`
+	XAEPResource     *XAEPResource     `json:"x-aep-resource,omitempty"`
+	XAEPFieldNumbers map[int]string    `json:"x-aep-field-numbers,omitempty"`
+	// XAEPSchemaVersion overrides the state schema version derived from the
+	// field numbers of a resource.
+	XAEPSchemaVersion *int64 `json:"x-aep-schema-version,omitempty"`
+	// XAEPFieldBehavior lists the AEP field behaviors (AEP-203), such as
+	// IMMUTABLE.
+	XAEPFieldBehavior []string `json:"x-aep-field-behavior,omitempty"`
+	// XAEPSensitive marks a field whose value must not be shown, such as an
+	// API key that the server does return.
+	XAEPSensitive bool `json:"x-aep-sensitive,omitempty"`
+
+	// Enum holds the allowed values, as decoded from JSON.
+	Enum []interface{} `json:"enum,omitempty"`
+	// Default is the value the server uses if none is given, as decoded from
+	// JSON.
+	Default interface{} `json:"default,omitempty"`
+	// AdditionalProperties is either a boolean or the schema of the values of
+	// a map. See additionalProperties.
+	AdditionalProperties json.RawMessage `json:"additionalProperties,omitempty"`
+
+	// MinLength, MaxLength and Pattern constrain strings.
+	MinLength *int64 `json:"minLength,omitempty"`
+	MaxLength *int64 `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	// Minimum and Maximum bound numbers. ExclusiveMinimum and ExclusiveMaximum
+	// are booleans in OpenAPI 3.0 and bounds of their own in 3.1.
+	Minimum          *float64    `json:"minimum,omitempty"`
+	Maximum          *float64    `json:"maximum,omitempty"`
+	ExclusiveMinimum interface{} `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum interface{} `json:"exclusiveMaximum,omitempty"`
+	// MinItems and MaxItems bound the length of arrays. UniqueItems arrays
+	// are sets, whose order doesn't matter.
+	MinItems    *int64 `json:"minItems,omitempty"`
+	MaxItems    *int64 `json:"maxItems,omitempty"`
+	UniqueItems bool   `json:"uniqueItems,omitempty"`
+
+	// Deprecated marks a field, or a resource, that is going away.
+	Deprecated bool `json:"deprecated,omitempty"`
+	// Example holds an example value, as decoded from JSON. Examples is the
+	// list of examples of OpenAPI 3.1. See examples.
+	Example  interface{}     `json:"example,omitempty"`
+	Examples json.RawMessage `json:"examples,omitempty"`
+}
+
+// withAnnotations returns a copy of s, the target of ref, with the annotations
+// that ref adds next to $ref applied to it.
+func (s *Schema) withAnnotations(ref *Schema) *Schema {
+	annotated := *s
+	if ref.Description != "" {
+		annotated.Description = ref.Description
+	}
+	annotated.ReadOnly = s.ReadOnly || ref.ReadOnly
+	annotated.WriteOnly = s.WriteOnly || ref.WriteOnly
+	annotated.XAEPSensitive = s.XAEPSensitive || ref.XAEPSensitive
+	if len(ref.XAEPFieldBehavior) > 0 {
+		annotated.XAEPFieldBehavior = append(append([]string{}, s.XAEPFieldBehavior...), ref.XAEPFieldBehavior...)
+	}
+	if ref.Title != "" {
+		annotated.Title = ref.Title
+	}
+	annotated.Deprecated = s.Deprecated || ref.Deprecated
+	if ref.Example != nil || len(ref.Examples) > 0 {
+		annotated.Example, annotated.Examples = ref.Example, ref.Examples
+	}
+	return &annotated
+}
+
+// stringEncoded reports whether s is an integer that the API sends as a JSON
+// string, as a string with the int64 format is. Such strings are integers in
+// Terraform.
+func (s *Schema) stringEncoded() bool {
+	return s.Type == "string" && s.Format == "int64"
+}
+
+// valueType returns the type of the values of s, which is "integer" for
+// strings that encode integers.
+func (s *Schema) valueType() string {
+	if s.stringEncoded() {
+		return "integer"
+	}
+	return s.Type
+}
+
+// examples returns the example values of the schema. Examples that aren't a
+// list, as some documents have, are ignored.
+func (s *Schema) examples() []interface{} {
+	var examples []interface{}
+	if len(s.Examples) > 0 {
+		_ = json.Unmarshal(s.Examples, &examples)
+	}
+	if s.Example != nil {
+		examples = append([]interface{}{s.Example}, examples...)
+	}
+	return examples
+}
+
+// sensitive reports whether the value of the schema is a secret.
+func (s *Schema) sensitive() bool {
+	return s.WriteOnly || s.Format == "password" || s.XAEPSensitive
+}
+
+// immutable reports whether the field can only be set on create.
+func (s *Schema) immutable() bool {
+	for _, behavior := range s.XAEPFieldBehavior {
+		if behavior == "IMMUTABLE" {
+			return true
+		}
+	}
+	return false
+}
+
+// variants returns the alternatives of a oneOf or anyOf schema. A "null"
+// alternative only makes the schema nullable, so it isn't a variant.
+func (s *Schema) variants() []Schema {
+	alternatives := s.OneOf
+	if len(alternatives) == 0 {
+		alternatives = s.AnyOf
+	}
+	var variants []Schema
+	for _, v := range alternatives {
+		if v.Type != "null" {
+			variants = append(variants, v)
+		}
+	}
+	return variants
+}
+
+// additionalProperties returns the schema of the values of a map, or nil if
+// properties other than those listed aren't allowed. Values of any type, as
+// allowed by true or {}, have an empty schema.
+func (s *Schema) additionalProperties() *Schema {
+	if len(s.AdditionalProperties) == 0 {
+		return nil
+	}
+	var allowed bool
+	if err := json.Unmarshal(s.AdditionalProperties, &allowed); err == nil {
+		if allowed {
+			return &Schema{}
+		}
+		return nil
+	}
+	values := &Schema{}
+	if err := json.Unmarshal(s.AdditionalProperties, values); err != nil {
+		return nil
+	}
+	return values
+}
+
+// lowerBound returns the lower bound of a number, if any, and whether the
+// bound itself is excluded.
+func (s *Schema) lowerBound() (*float64, bool) {
+	return bound(s.Minimum, s.ExclusiveMinimum)
+}
+
+// upperBound returns the upper bound of a number, if any, and whether the
+// bound itself is excluded.
+func (s *Schema) upperBound() (*float64, bool) {
+	return bound(s.Maximum, s.ExclusiveMaximum)
+}
+
+func bound(inclusive *float64, exclusive interface{}) (*float64, bool) {
+	switch e := exclusive.(type) {
+	case float64:
+		return &e, true
+	case bool:
+		return inclusive, e && inclusive != nil
+	default:
+		return inclusive, false
+	}
+}
+
+// XAEPResource is the part of the x-aep-resource extension used to find the
+// schema of a resource.
+type XAEPResource struct {
+	Singular    string `json:"singular,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Spec holds the component schemas of an OpenAPI document.
+type Spec struct {
+	Schemas map[string]Schema
+
+	// RecursionDepth is the number of times a recursive schema is expanded
+	// inside itself. See DefaultRecursionDepth.
+	RecursionDepth int
+
+	// DynamicValues makes free-form values dynamic attributes instead of
+	// JSON strings.
+	DynamicValues bool
+}
+
+// ParseSpec reads the component schemas from an OpenAPI document in JSON or
+// YAML.
+func ParseSpec(b []byte) (*Spec, error) {
+	var doc struct {
+		Components struct {
+			Schemas map[string]Schema `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		// YAML is decoded generically and then converted, so that the JSON
+		// tags above apply to both formats.
+		var generic interface{}
+		if yamlErr := yaml.Unmarshal(b, &generic); yamlErr != nil {
+			return nil, fmt.Errorf("unable to parse OpenAPI spec: %w", err)
+		}
+		converted, err := json.Marshal(generic)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse OpenAPI spec: %w", err)
+		}
+		if err := json.Unmarshal(converted, &doc); err != nil {
+			return nil, fmt.Errorf("unable to parse OpenAPI spec: %w", err)
+		}
+	}
+	return &Spec{Schemas: doc.Components.Schemas, RecursionDepth: DefaultRecursionDepth}, nil
+}
+
+// SpecFromOpenAPI converts the component schemas of o. Keywords that
+// openapi.Schema doesn't carry are lost, so prefer ParseSpec when the document
+// is available.
+func SpecFromOpenAPI(o *openapi.OpenAPI) (*Spec, error) {
+	spec := &Spec{Schemas: make(map[string]Schema), RecursionDepth: DefaultRecursionDepth}
+	if o == nil {
+		return spec, nil
+	}
+	for name, s := range o.Components.Schemas {
+		converted, err := schemaFromOpenAPI(&s)
+		if err != nil {
+			return nil, err
+		}
+		spec.Schemas[name] = *converted
+	}
+	return spec, nil
+}
+
+// schemaFromOpenAPI converts s to a Schema.
+func schemaFromOpenAPI(s *openapi.Schema) (*Schema, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	converted := &Schema{}
+	if err := json.Unmarshal(b, converted); err != nil {
+		return nil, err
+	}
+	return converted, nil
+}
+
+// ResourceSchema returns the schema of r, falling back to the schema parsed
+// by aep-lib-go if the spec has no schema for it.
+func (s *Spec) ResourceSchema(r *api.Resource) (*Schema, error) {
+	_, schema, err := s.resourceSchema(r)
+	return schema, err
+}
+
+// resourceSchema is ResourceSchema, but also returns the reference to the
+// schema of r if it is one of the component schemas.
+func (s *Spec) resourceSchema(r *api.Resource) (string, *Schema, error) {
+	for name, schema := range s.Schemas {
+		if schema.XAEPResource != nil && schema.XAEPResource.Singular == r.Singular {
+			return componentSchemaPrefix + name, &schema, nil
+		}
+	}
+	schema, err := schemaFromOpenAPI(r.Schema)
+	return "", schema, err
+}
+
+// FlattenAllOf merges the schemas of an allOf into a single schema.
+//
+// Properties, required fields and field numbers are combined. Any other
+// keyword keeps the first value set, starting with schema itself.
+func (s *Spec) FlattenAllOf(schema *Schema) (*Schema, error) {
+	return s.flattenAllOf(schema, nil)
+}
+
+// flattenAllOf flattens schema. parts holds the references being flattened,
+// as an allOf that includes itself can't be flattened.
+func (s *Spec) flattenAllOf(schema *Schema, parts []string) (*Schema, error) {
+	if len(schema.AllOf) == 0 {
+		return schema, nil
+	}
+	base := *schema
+	base.AllOf = nil
+	merged, err := schemaMap(&base)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, part := range schema.AllOf {
+		for _, ref := range parts {
+			if part.Ref != "" && part.Ref == ref {
+				return nil, fmt.Errorf("allOf of %s includes itself", ref)
+			}
+		}
+		resolved, err := s.Dereference(&part)
+		if err != nil {
+			return nil, err
+		}
+		resolved, err = s.flattenAllOf(resolved, append(parts[:len(parts):len(parts)], part.Ref))
+		if err != nil {
+			return nil, err
+		}
+		partMap, err := schemaMap(resolved)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range partMap {
+			existing, ok := merged[key]
+			switch {
+			case !ok:
+				merged[key] = value
+			case key == "properties" || key == "x-aep-field-numbers":
+				for k, v := range value.(map[string]interface{}) {
+					existing.(map[string]interface{})[k] = v
+				}
+			case key == "required" || key == "x-aep-field-behavior":
+				merged[key] = append(existing.([]interface{}), value.([]interface{})...)
+			}
+		}
+	}
+
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	flattened := &Schema{}
+	if err := json.Unmarshal(b, flattened); err != nil {
+		return nil, err
+	}
+	if flattened.Type == "" && len(flattened.Properties) > 0 {
+		flattened.Type = "object"
+	}
+	return flattened, nil
+}
+
+// schemaMap returns the keywords of schema as a generic JSON object.
+func schemaMap(schema *Schema) (map[string]interface{}, error) {
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// resolve dereferences schema and flattens its allOf, for reading its own
+// keywords, such as the enum of the items of a list.
+func (s *Spec) resolve(schema *Schema) (*Schema, error) {
+	resolved, err := s.Dereference(schema)
+	if err != nil {
+		return nil, err
+	}
+	return s.FlattenAllOf(resolved)
+}
+
+// resolveItems returns prop with its items resolved, or prop itself if it has
+// no items.
+func (s *Spec) resolveItems(prop *Schema) (*Schema, error) {
+	if prop.Items == nil {
+		return prop, nil
+	}
+	items, err := s.resolve(prop.Items)
+	if err != nil {
+		return nil, err
+	}
+	resolved := *prop
+	resolved.Items = items
+	return &resolved, nil
+}
+
+// Dereference returns the schema that schema refers to, or schema itself if it
+// isn't a reference.
+func (s *Spec) Dereference(schema *Schema) (*Schema, error) {
+	if schema.Ref == "" {
+		return schema, nil
+	}
+	if !strings.HasPrefix(schema.Ref, componentSchemaPrefix) {
+		return nil, fmt.Errorf("unsupported reference %s", schema.Ref)
+	}
+	resolved, ok := s.Schemas[strings.TrimPrefix(schema.Ref, componentSchemaPrefix)]
+	if !ok {
+		return nil, fmt.Errorf("ref not found for %s", schema.Ref)
+	}
+	return &resolved, nil
+}
@@ -0,0 +1,69 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSpec(t *testing.T) {
+	want := map[string]Schema{
+		"thing": {
+			Type: "object",
+			Properties: map[string]Schema{
+				"color": {Type: "string", Enum: []interface{}{"RED", "GREEN"}},
+				"part":  {Ref: "#/components/schemas/part"},
+			},
+		},
+		"part": {Type: "object"},
+	}
+	tests := map[string]string{
+		"json": `{"components": {"schemas": {
+  "thing": {"type": "object", "properties": {
+    "color": {"type": "string", "enum": ["RED", "GREEN"]},
+    "part": {"$ref": "#/components/schemas/part"}}},
+  "part": {"type": "object"}}}}`,
+		"yaml": `
+components:
+  schemas:
+    thing:
+      type: object
+      properties:
+        color:
+          type: string
+          enum: [RED, GREEN]
+        part:
+          $ref: '#/components/schemas/part'
+    part:
+      type: object
+`,
+	}
+	for name, doc := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec, err := ParseSpec([]byte(doc))
+			if err != nil {
+				t.Fatalf("ParseSpec() error = %v", err)
+			}
+			if d := cmp.Diff(spec.Schemas, want); d != "" {
+				t.Errorf("ParseSpec() diff: %s", d)
+			}
+
+			thing := spec.Schemas["thing"]
+			part := thing.Properties["part"]
+			got, err := spec.Dereference(&part)
+			if err != nil {
+				t.Fatalf("Dereference() error = %v", err)
+			}
+			if got.Type != "object" {
+				t.Errorf("Dereference() = %v, want the part schema", got)
+			}
+		})
+	}
+}
+
+func TestDereferenceNotFound(t *testing.T) {
+	spec := &Spec{Schemas: map[string]Schema{}}
+	if _, err := spec.Dereference(&Schema{Ref: "#/components/schemas/missing"}); err == nil {
+		t.Error("Dereference() succeeded for a missing schema")
+	}
+}
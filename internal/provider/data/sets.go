@@ -0,0 +1,69 @@
+package data
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	tfschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+)
+
+// setAttribute fills in m for an array with uniqueItems, whose items are
+// described by item and have the Terraform type t. The order of a set doesn't
+// matter, so reordering by the server never shows up as a diff.
+func setAttribute(ctx context.Context, m *ResourceAttribute, prop *Schema, item *ResourceAttribute, t attr.Type, required bool, computed bool, description string) {
+	sensitive := prop.sensitive()
+	m.Type = SET
+
+	if item.Type == OBJECT {
+		no := item.NestedAttributes
+		m.NestedAttributes = no
+		m.Attribute = tfschema.SetNestedAttribute{
+			NestedObject: tfschema.NestedAttributeObject{
+				Attributes: convertToMap(no),
+			},
+			MarkdownDescription: description,
+			DeprecationMessage:  deprecationMessage(prop),
+			Sensitive:           sensitive,
+			Computed:            computed,
+			Required:            required,
+			Optional:            !required,
+			Validators:          setValidators(ctx, prop),
+			PlanModifiers:       setPlanModifiers(prop, computed),
+		}
+		m.DatasourceAttribute = dsschema.SetNestedAttribute{
+			NestedObject: dsschema.NestedAttributeObject{
+				Attributes: convertToMapForDatasource(no),
+			},
+			MarkdownDescription: description,
+			Sensitive:           sensitive,
+			Computed:            true,
+		}
+		return
+	}
+
+	// Defaults are only planned for sets of primitives.
+	var setDefaultValue defaults.Set
+	if item.ItemAttribute == nil && item.NestedAttributes == nil && item.Type != JSON_OBJECT {
+		setDefaultValue = setDefault(ctx, prop, t)
+	}
+	m.Attribute = tfschema.SetAttribute{
+		ElementType:         t,
+		MarkdownDescription: description,
+		DeprecationMessage:  deprecationMessage(prop),
+		Sensitive:           sensitive,
+		Computed:            computed,
+		Required:            required,
+		Optional:            !required,
+		Validators:          setValidators(ctx, prop),
+		Default:             setDefaultValue,
+		PlanModifiers:       setPlanModifiers(prop, computed),
+	}
+	m.DatasourceAttribute = dsschema.SetAttribute{
+		ElementType:         t,
+		MarkdownDescription: description,
+		Sensitive:           sensitive,
+		Computed:            true,
+	}
+}
@@ -0,0 +1,287 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aep-dev/aep-lib-go/pkg/api"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// FieldNumbersAttribute records the field number of every attribute in state,
+// so that renamed fields can be found when the state is upgraded.
+const FieldNumbersAttribute = "aep_field_numbers"
+
+// schemaVersion returns the state schema version of the resource r: the
+// x-aep-schema-version of its schema s. Terraform only upgrades state whose
+// version is lower, so the version can't be derived from the field numbers:
+// renaming or retyping a field doesn't change them, and deleting the highest
+// one would lower it. Without x-aep-schema-version the version is 0, with a
+// warning in diags, and the state is never upgraded.
+func schemaVersion(r *api.Resource, s *Schema, diags *diag.Diagnostics) int64 {
+	if s.XAEPSchemaVersion != nil {
+		return *s.XAEPSchemaVersion
+	}
+	diags.AddWarning("Schema version missing", fmt.Sprintf(
+		"Resource %q has x-aep-field-numbers but no x-aep-schema-version, so its state is never upgraded. Set x-aep-schema-version and bump it whenever a field is renamed or retyped.",
+		r.Singular))
+	return 0
+}
+
+// fieldNumbers maps the field number path of every numbered attribute, such as
+// "4.1", to its Terraform path, such as "network.subnet".
+func (r *ResourceSchema) fieldNumbers() map[string]string {
+	result := make(map[string]string)
+	var walk func(attributes map[string]*ResourceAttribute, numberPrefix, namePrefix string)
+	walk = func(attributes map[string]*ResourceAttribute, numberPrefix, namePrefix string) {
+		for _, a := range attributes {
+			if a.FieldNumber == nil || a.Internal {
+				continue
+			}
+			number := numberPrefix + strconv.Itoa(*a.FieldNumber)
+			name := namePrefix + a.TerraformName
+			result[number] = name
+			walk(a.NestedAttributes, number+".", name+".")
+		}
+	}
+	walk(r.Attributes, "", "")
+	return result
+}
+
+// FieldNumbersValue returns the value of the FieldNumbersAttribute.
+func (r *ResourceSchema) FieldNumbersValue() Value {
+	values := make(map[string]Value)
+	for number, name := range r.fieldNumbers() {
+		values[number] = Value{String: String(name)}
+	}
+	return Value{Map: &values}
+}
+
+func fieldNumbersAttribute(fieldNumbers map[string]string) *ResourceAttribute {
+	elements := make(map[string]attr.Value, len(fieldNumbers))
+	for number, name := range fieldNumbers {
+		elements[number] = types.StringValue(name)
+	}
+	description := "The field number of every attribute, used to upgrade the state when fields are renamed. Managed by the provider."
+	return &ResourceAttribute{
+		TerraformName: FieldNumbersAttribute,
+		JSONName:      FieldNumbersAttribute,
+		Internal:      true,
+		Type:          MAP,
+		Attribute: tfschema.MapAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: description,
+			Computed:            true,
+			PlanModifiers: []planmodifier.Map{
+				fieldNumbersModifier{value: types.MapValueMust(types.StringType, elements)},
+			},
+		},
+		DatasourceAttribute: dsschema.MapAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: description,
+			Computed:            true,
+		},
+	}
+}
+
+// fieldNumbersModifier plans the field numbers of the current spec when the
+// resource is created or updated, since the state written then records them.
+// Otherwise the value in state is kept: a spec with new field numbers, or state
+// that has none yet, doesn't plan an update by itself. Read records the current
+// field numbers instead.
+type fieldNumbersModifier struct {
+	value types.Map
+}
+
+func (m fieldNumbersModifier) Description(_ context.Context) string {
+	return "the field numbers of the current spec are recorded when the resource is created or updated"
+}
+
+func (m fieldNumbersModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m fieldNumbersModifier) PlanModifyMap(_ context.Context, req planmodifier.MapRequest, resp *planmodifier.MapResponse) {
+	if req.PlanValue.IsUnknown() {
+		resp.PlanValue = m.value
+	}
+}
+
+// UpgradeState converts prior, the JSON state written with an older version
+// of the resource's schema, to the current schema.
+//
+// Attributes are matched by field number where the prior state recorded one,
+// and by name otherwise, so renamed fields keep their values. Values are
+// converted to the current type where possible. Attributes that are new, or
+// whose value can't be converted, start out null.
+func (r *ResourceSchema) UpgradeState(prior map[string]interface{}) map[string]interface{} {
+	oldNames := make(map[string]string)
+	if recorded, ok := prior[FieldNumbersAttribute].(map[string]interface{}); ok {
+		for number, name := range recorded {
+			if name, ok := name.(string); ok {
+				oldNames[number] = name
+			}
+		}
+	}
+
+	top := ""
+	upgraded := upgradeObject(prior, r.Attributes, &top, oldNames)
+	if _, ok := r.Attributes[FieldNumbersAttribute]; ok {
+		fieldNumbers := make(map[string]interface{})
+		for number, name := range r.fieldNumbers() {
+			fieldNumbers[number] = name
+		}
+		upgraded[FieldNumbersAttribute] = fieldNumbers
+	}
+	return upgraded
+}
+
+// upgradeObject upgrades the attributes of an object. numberPrefix is the
+// field number path of the object followed by a ".", or empty at the top
+// level. It is nil if the object has no field number, in which case its
+// attributes are only matched by name.
+func upgradeObject(prior map[string]interface{}, attributes map[string]*ResourceAttribute, numberPrefix *string, oldNames map[string]string) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, a := range attributes {
+		if a.Internal {
+			continue
+		}
+		oldName := a.TerraformName
+		var number *string
+		if a.FieldNumber != nil && numberPrefix != nil {
+			n := *numberPrefix + strconv.Itoa(*a.FieldNumber)
+			number = &n
+			if path, ok := oldNames[n]; ok {
+				oldName = path[strings.LastIndex(path, ".")+1:]
+			}
+		}
+		v, ok := prior[oldName]
+		if !ok || v == nil {
+			continue
+		}
+		if converted, ok := upgradeValue(v, a, number, oldNames); ok {
+			result[a.TerraformName] = converted
+		}
+	}
+	return result
+}
+
+// upgradeValue converts v to the type of a, if possible. number is the field
+// number path of the attribute, if known.
+func upgradeValue(v interface{}, a *ResourceAttribute, number *string, oldNames map[string]string) (interface{}, bool) {
+	switch a.Type {
+	case STRING:
+		switch v := v.(type) {
+		case string:
+			return v, true
+		case json.Number:
+			return v.String(), true
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), true
+		case bool:
+			return strconv.FormatBool(v), true
+		}
+	case JSON_OBJECT:
+		if s, ok := v.(string); ok {
+			return s, true
+		}
+		if value, ok := dynamicStateValue(v); ok {
+			v = value
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, false
+		}
+		return string(b), true
+	case DYNAMIC:
+		if _, ok := dynamicStateValue(v); ok {
+			return v, true
+		}
+		// Free-form values used to be stored as JSON strings.
+		if s, ok := v.(string); ok {
+			d := json.NewDecoder(strings.NewReader(s))
+			d.UseNumber()
+			if err := d.Decode(&v); err != nil {
+				v = s
+			}
+		}
+		return map[string]interface{}{"value": v, "type": dynamicStateType(v)}, true
+	case NUMBER, INTEGER:
+		switch v := v.(type) {
+		case json.Number, float64:
+			return v, true
+		case string:
+			if _, err := strconv.ParseFloat(v, 64); err == nil {
+				return json.Number(v), true
+			}
+		}
+	case BOOLEAN:
+		switch v := v.(type) {
+		case bool:
+			return v, true
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, true
+			}
+		}
+	case OBJECT:
+		if m, ok := v.(map[string]interface{}); ok {
+			var prefix *string
+			if number != nil {
+				p := *number + "."
+				prefix = &p
+			}
+			return upgradeObject(m, a.NestedAttributes, prefix, oldNames), true
+		}
+	case ARRAY, SET:
+		items, ok := v.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		result := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			if item == nil {
+				return nil, false
+			}
+			converted, ok := upgradeValue(item, a.item(), number, oldNames)
+			if !ok {
+				return nil, false
+			}
+			result = append(result, converted)
+		}
+		return result, true
+	case MAP:
+		// Maps used to be stored as JSON strings.
+		if s, ok := v.(string); ok {
+			d := json.NewDecoder(strings.NewReader(s))
+			d.UseNumber()
+			if err := d.Decode(&v); err != nil {
+				return nil, false
+			}
+		}
+		values, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		result := make(map[string]interface{}, len(values))
+		for key, value := range values {
+			converted, ok := upgradeValue(value, a.item(), nil, oldNames)
+			if !ok {
+				return nil, false
+			}
+			result[key] = converted
+		}
+		return result, true
+	default:
+		return v, true
+	}
+	return nil, false
+}
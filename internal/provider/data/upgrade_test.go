@@ -0,0 +1,251 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	tfschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const upgradeTestSpec = `{
+  "components": {
+    "schemas": {
+      "thing": {
+        "x-aep-resource": {"singular": "thing"},
+        "properties": {
+          "display_name": {"type": "string"},
+          "size": {"type": "string"},
+          "network": {
+            "type": "object",
+            "properties": {"subnet_id": {"type": "string"}},
+            "x-aep-field-numbers": {"1": "subnet_id"}
+          },
+          "labels": {"type": "array", "items": {"type": "string"}},
+          "path": {"type": "string"}
+        },
+        "x-aep-field-numbers": {"1": "display_name", "2": "size", "3": "network", "5": "labels", "10018": "path"},
+        "x-aep-schema-version": 2
+      }
+    }
+  }
+}`
+
+func TestSchemaVersion(t *testing.T) {
+	s := newTestResourceSchema(t, upgradeTestSpec)
+	if s.Version != 2 {
+		t.Errorf("Version = %d, want 2", s.Version)
+	}
+
+	want := map[string]string{
+		"1":     "display_name",
+		"2":     "size",
+		"3":     "network",
+		"3.1":   "network.subnet_id",
+		"5":     "labels",
+		"10018": "path",
+	}
+	if d := cmp.Diff(s.fieldNumbers(), want); d != "" {
+		t.Errorf("fieldNumbers() diff: %s", d)
+	}
+	if a, ok := s.Attributes[FieldNumbersAttribute]; !ok || !a.Internal {
+		t.Errorf("%s is missing or not internal", FieldNumbersAttribute)
+	}
+	if _, ok := s.SchemaAttributes()[FieldNumbersAttribute]; ok {
+		t.Errorf("%s would be sent to the server", FieldNumbersAttribute)
+	}
+}
+
+func TestSchemaVersionChanges(t *testing.T) {
+	// Each spec is a later version of the thing with the name field 1 and
+	// the size field 2, at x-aep-schema-version 1.
+	tests := []struct {
+		name         string
+		schema       string
+		wantVersion  int64
+		wantWarnings int
+	}{
+		{
+			name: "renamed",
+			schema: `"x-aep-schema-version": 2,
+  "properties": {"title": {"type": "string"}, "size": {"type": "integer"}},
+  "x-aep-field-numbers": {"1": "title", "2": "size"}`,
+			wantVersion: 2,
+		},
+		{
+			name: "retyped",
+			schema: `"x-aep-schema-version": 2,
+  "properties": {"name": {"type": "string"}, "size": {"type": "string"}},
+  "x-aep-field-numbers": {"1": "name", "2": "size"}`,
+			wantVersion: 2,
+		},
+		{
+			name: "highest field deleted",
+			schema: `"x-aep-schema-version": 1,
+  "properties": {"name": {"type": "string"}},
+  "x-aep-field-numbers": {"1": "name"}`,
+			wantVersion: 1,
+		},
+		{
+			name: "without x-aep-schema-version",
+			schema: `"properties": {"name": {"type": "string"}, "size": {"type": "integer"}},
+  "x-aep-field-numbers": {"1": "name", "2": "size"}`,
+			wantVersion:  0,
+			wantWarnings: 1,
+		},
+		{
+			name:        "without field numbers",
+			schema:      `"properties": {"name": {"type": "string"}, "size": {"type": "integer"}}`,
+			wantVersion: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestResourceSchema(t, `{"components": {"schemas": {"thing": {
+  "x-aep-resource": {"singular": "thing"},
+  `+tt.schema+`}}}}`)
+			if s.Version != tt.wantVersion {
+				t.Errorf("Version = %d, want %d", s.Version, tt.wantVersion)
+			}
+			if got := s.Diagnostics.WarningsCount(); got != tt.wantWarnings {
+				t.Errorf("got %d warnings, want %d: %v", got, tt.wantWarnings, s.Diagnostics)
+			}
+			if _, ok := s.Attributes[FieldNumbersAttribute]; ok != strings.Contains(tt.schema, "x-aep-field-numbers") {
+				t.Errorf("%s present = %v", FieldNumbersAttribute, ok)
+			}
+		})
+	}
+}
+
+func TestFieldNumbersPlan(t *testing.T) {
+	s := newTestResourceSchema(t, upgradeTestSpec)
+	a := s.Attributes[FieldNumbersAttribute].Attribute.(tfschema.MapAttribute)
+	if a.Default != nil {
+		t.Fatalf("%s has a default, which plans an update whenever it changes", FieldNumbersAttribute)
+	}
+
+	elements := make(map[string]attr.Value)
+	for number, name := range s.fieldNumbers() {
+		elements[number] = types.StringValue(name)
+	}
+	current := types.MapValueMust(types.StringType, elements)
+	old := types.MapValueMust(types.StringType, map[string]attr.Value{"1": types.StringValue("title")})
+	tests := []struct {
+		name string
+		plan types.Map
+		want types.Map
+	}{
+		// The resource is created or updated.
+		{"unknown", types.MapUnknown(types.StringType), current},
+		// Nothing else changes, so the state is planned as it is.
+		{"recorded", old, old},
+		{"not recorded yet", types.MapNull(types.StringType), types.MapNull(types.StringType)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &planmodifier.MapResponse{PlanValue: tt.plan}
+			for _, m := range a.PlanModifiers {
+				m.PlanModifyMap(context.TODO(), planmodifier.MapRequest{PlanValue: resp.PlanValue}, resp)
+			}
+			if !resp.PlanValue.Equal(tt.want) {
+				t.Errorf("planned %v, want %v", resp.PlanValue, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpgradeState(t *testing.T) {
+	s := newTestResourceSchema(t, upgradeTestSpec)
+
+	// The prior state was written when display_name was called title, size
+	// was an integer and the subnet was called subnet.
+	prior := decodeState(t, `{
+  "title": "Example",
+  "size": 3,
+  "network": {"subnet": "default"},
+  "labels": ["a", "b"],
+  "removed": "gone",
+  "path": "things/example",
+  "id": "things/example",
+  "aep_field_numbers": {"1": "title", "2": "size", "3": "network", "3.1": "network.subnet", "4": "removed", "10018": "path"}
+}`)
+
+	want := decodeState(t, `{
+  "display_name": "Example",
+  "size": "3",
+  "network": {"subnet_id": "default"},
+  "labels": ["a", "b"],
+  "path": "things/example",
+  "id": "things/example",
+  "aep_field_numbers": {"1": "display_name", "2": "size", "3": "network", "3.1": "network.subnet_id", "5": "labels", "10018": "path"}
+}`)
+	if d := cmp.Diff(s.UpgradeState(prior), want); d != "" {
+		t.Errorf("UpgradeState() diff: %s", d)
+	}
+}
+
+func TestUpgradeStateWithoutFieldNumbers(t *testing.T) {
+	s := newTestResourceSchema(t, upgradeTestSpec)
+
+	// State written before field numbers were recorded is matched by name.
+	prior := decodeState(t, `{"display_name": "Example", "size": true, "labels": "not a list"}`)
+	got := s.UpgradeState(prior)
+	if got["display_name"] != "Example" || got["size"] != "true" {
+		t.Errorf("UpgradeState() = %v, want display_name and size kept", got)
+	}
+	if _, ok := got["labels"]; ok {
+		t.Errorf("UpgradeState() kept labels %v, which can't be converted", got["labels"])
+	}
+}
+
+func decodeState(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+	var state map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &state); err != nil {
+		t.Fatalf("invalid state %s: %v", s, err)
+	}
+	return state
+}
+
+func TestUpgradeMapFromJSON(t *testing.T) {
+	// Maps were stored as JSON strings before they had a type of their own.
+	got, ok := upgradeValue(`{"env": "prod", "team": "web"}`, &ResourceAttribute{Type: MAP, ListItemType: STRING}, nil, nil)
+	if !ok {
+		t.Fatal("upgradeValue() couldn't convert the JSON string")
+	}
+	want := map[string]interface{}{"env": "prod", "team": "web"}
+	if d := cmp.Diff(got, want); d != "" {
+		t.Errorf("upgradeValue() diff: %s", d)
+	}
+}
+
+func TestUpgradeDynamic(t *testing.T) {
+	dynamic := &ResourceAttribute{Type: DYNAMIC}
+	// Free-form values were stored as JSON strings before they were dynamic.
+	got, ok := upgradeValue(`{"ports": [80], "name": "web"}`, dynamic, nil, nil)
+	if !ok {
+		t.Fatal("upgradeValue() couldn't convert the JSON string")
+	}
+	want := map[string]interface{}{
+		"value": map[string]interface{}{"ports": []interface{}{json.Number("80")}, "name": "web"},
+		"type":  []interface{}{"object", map[string]interface{}{"ports": []interface{}{"tuple", []interface{}{"number"}}, "name": "string"}},
+	}
+	if d := cmp.Diff(got, want); d != "" {
+		t.Errorf("upgradeValue() diff: %s", d)
+	}
+
+	// And back.
+	back, ok := upgradeValue(got, &ResourceAttribute{Type: JSON_OBJECT}, nil, nil)
+	if !ok {
+		t.Fatal("upgradeValue() couldn't convert the dynamic value")
+	}
+	if want := `{"name":"web","ports":[80]}`; back != want {
+		t.Errorf("upgradeValue() = %v, want %s", back, want)
+	}
+}
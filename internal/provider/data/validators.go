@@ -0,0 +1,301 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/helpers/validatordiag"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// attributeDescription returns the title and description of prop, followed
+// by its field behaviors, the values allowed by its enum or the enum of its
+// items, its examples and its default.
+func attributeDescription(prop *Schema) string {
+	var paragraphs []string
+	if prop.Title != "" && !strings.HasPrefix(prop.Description, prop.Title) {
+		paragraphs = append(paragraphs, fmt.Sprintf("**%s**", prop.Title))
+	}
+	if prop.Description != "" {
+		paragraphs = append(paragraphs, prop.Description)
+	}
+	if len(prop.XAEPFieldBehavior) > 0 {
+		paragraphs = append(paragraphs, fmt.Sprintf("Field behavior: `%s`.", strings.Join(prop.XAEPFieldBehavior, "`, `")))
+	}
+
+	enum := prop.Enum
+	if len(enum) == 0 && prop.Items != nil {
+		enum = prop.Items.Enum
+	}
+	if len(enum) > 0 {
+		values := make([]string, 0, len(enum))
+		for _, v := range enum {
+			if v == nil {
+				continue
+			}
+			values = append(values, fmt.Sprintf("`%v`", v))
+		}
+		paragraphs = append(paragraphs, fmt.Sprintf("Must be one of: %s.", strings.Join(values, ", ")))
+	}
+
+	if examples := prop.examples(); len(examples) > 0 {
+		values := make([]string, len(examples))
+		for i, v := range examples {
+			values[i] = fmt.Sprintf("`%s`", formatDefault(v))
+		}
+		label := "Example"
+		if len(values) > 1 {
+			label = "Examples"
+		}
+		paragraphs = append(paragraphs, fmt.Sprintf("%s: %s.", label, strings.Join(values, ", ")))
+	}
+
+	if prop.Default != nil {
+		paragraphs = append(paragraphs, fmt.Sprintf("Defaults to `%s`.", formatDefault(prop.Default)))
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// deprecationMessage returns the message shown when a deprecated field is
+// configured, or nothing if prop isn't deprecated.
+func deprecationMessage(prop *Schema) string {
+	if !prop.Deprecated {
+		return ""
+	}
+	return "This field is deprecated by the API and may be removed in a future version."
+}
+
+// formatDefault formats a default as it would be written in JSON.
+func formatDefault(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+func stringValidators(ctx context.Context, prop *Schema) []validator.String {
+	var validators []validator.String
+	if values := stringEnum(prop.Enum); len(values) > 0 {
+		validators = append(validators, stringvalidator.OneOf(values...))
+	}
+	if prop.MinLength != nil {
+		validators = append(validators, stringvalidator.LengthAtLeast(int(*prop.MinLength)))
+	}
+	if prop.MaxLength != nil {
+		validators = append(validators, stringvalidator.LengthAtMost(int(*prop.MaxLength)))
+	}
+	if prop.Pattern != "" {
+		// OpenAPI patterns are ECMA-262 regular expressions. Those that RE2
+		// can't compile are left for the server to enforce.
+		if re, err := regexp.Compile(prop.Pattern); err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("ignoring pattern %q that can't be compiled: %v", prop.Pattern, err))
+		} else {
+			validators = append(validators, stringvalidator.RegexMatches(re, ""))
+		}
+	}
+	if valid, ok := formatChecks[prop.Format]; ok {
+		validators = append(validators, formatValidator{format: prop.Format, valid: valid})
+	}
+	return validators
+}
+
+func int64Validators(prop *Schema) []validator.Int64 {
+	var validators []validator.Int64
+	if values := int64Enum(prop.Enum); len(values) > 0 {
+		validators = append(validators, int64validator.OneOf(values...))
+	}
+	if min, exclusive := prop.lowerBound(); min != nil {
+		least := int64(math.Ceil(*min))
+		if exclusive {
+			least = int64(math.Floor(*min)) + 1
+		}
+		validators = append(validators, int64validator.AtLeast(least))
+	}
+	if max, exclusive := prop.upperBound(); max != nil {
+		most := int64(math.Floor(*max))
+		if exclusive {
+			most = int64(math.Ceil(*max)) - 1
+		}
+		validators = append(validators, int64validator.AtMost(most))
+	}
+	return validators
+}
+
+func numberValidators(prop *Schema) []validator.Number {
+	var validators []validator.Number
+	min, minExclusive := prop.lowerBound()
+	max, maxExclusive := prop.upperBound()
+	if min != nil || max != nil {
+		validators = append(validators, numberRangeValidator{
+			min:          min,
+			minExclusive: minExclusive,
+			max:          max,
+			maxExclusive: maxExclusive,
+		})
+	}
+	return validators
+}
+
+func listValidators(ctx context.Context, prop *Schema) []validator.List {
+	var validators []validator.List
+	if prop.MinItems != nil {
+		validators = append(validators, listvalidator.SizeAtLeast(int(*prop.MinItems)))
+	}
+	if prop.MaxItems != nil {
+		validators = append(validators, listvalidator.SizeAtMost(int(*prop.MaxItems)))
+	}
+	if prop.Items == nil {
+		return validators
+	}
+	switch prop.Items.valueType() {
+	case "string":
+		if items := stringValidators(ctx, prop.Items); len(items) > 0 {
+			validators = append(validators, listvalidator.ValueStringsAre(items...))
+		}
+	case "integer":
+		if items := int64Validators(prop.Items); len(items) > 0 {
+			validators = append(validators, listvalidator.ValueInt64sAre(items...))
+		}
+	case "number":
+		if items := numberValidators(prop.Items); len(items) > 0 {
+			validators = append(validators, listvalidator.ValueNumbersAre(items...))
+		}
+	}
+	return validators
+}
+
+func setValidators(ctx context.Context, prop *Schema) []validator.Set {
+	var validators []validator.Set
+	if prop.MinItems != nil {
+		validators = append(validators, setvalidator.SizeAtLeast(int(*prop.MinItems)))
+	}
+	if prop.MaxItems != nil {
+		validators = append(validators, setvalidator.SizeAtMost(int(*prop.MaxItems)))
+	}
+	if prop.Items == nil {
+		return validators
+	}
+	switch prop.Items.valueType() {
+	case "string":
+		if items := stringValidators(ctx, prop.Items); len(items) > 0 {
+			validators = append(validators, setvalidator.ValueStringsAre(items...))
+		}
+	case "integer":
+		if items := int64Validators(prop.Items); len(items) > 0 {
+			validators = append(validators, setvalidator.ValueInt64sAre(items...))
+		}
+	case "number":
+		if items := numberValidators(prop.Items); len(items) > 0 {
+			validators = append(validators, setvalidator.ValueNumbersAre(items...))
+		}
+	}
+	return validators
+}
+
+// mapValidators returns the validators of a map whose values have the schema
+// values.
+func mapValidators(ctx context.Context, values *Schema) []validator.Map {
+	var validators []validator.Map
+	switch values.valueType() {
+	case "string":
+		if items := stringValidators(ctx, values); len(items) > 0 {
+			validators = append(validators, mapvalidator.ValueStringsAre(items...))
+		}
+	case "integer":
+		if items := int64Validators(values); len(items) > 0 {
+			validators = append(validators, mapvalidator.ValueInt64sAre(items...))
+		}
+	case "number":
+		if items := numberValidators(values); len(items) > 0 {
+			validators = append(validators, mapvalidator.ValueNumbersAre(items...))
+		}
+	}
+	return validators
+}
+
+// numberRangeValidator checks that a number lies within bounds. The framework
+// validators only cover float and integer attributes, not numbers.
+type numberRangeValidator struct {
+	min          *float64
+	minExclusive bool
+	max          *float64
+	maxExclusive bool
+}
+
+func (v numberRangeValidator) Description(_ context.Context) string {
+	var parts []string
+	if v.min != nil {
+		op := ">="
+		if v.minExclusive {
+			op = ">"
+		}
+		parts = append(parts, fmt.Sprintf("%s %v", op, *v.min))
+	}
+	if v.max != nil {
+		op := "<="
+		if v.maxExclusive {
+			op = "<"
+		}
+		parts = append(parts, fmt.Sprintf("%s %v", op, *v.max))
+	}
+	return fmt.Sprintf("value must be %s", strings.Join(parts, " and "))
+}
+
+func (v numberRangeValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v numberRangeValidator) ValidateNumber(ctx context.Context, req validator.NumberRequest, resp *validator.NumberResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	value := req.ConfigValue.ValueBigFloat()
+	valid := true
+	if v.min != nil {
+		cmp := value.Cmp(big.NewFloat(*v.min))
+		valid = valid && (cmp > 0 || (cmp == 0 && !v.minExclusive))
+	}
+	if v.max != nil {
+		cmp := value.Cmp(big.NewFloat(*v.max))
+		valid = valid && (cmp < 0 || (cmp == 0 && !v.maxExclusive))
+	}
+	if !valid {
+		resp.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(req.Path, v.Description(ctx), value.String()))
+	}
+}
+
+// stringEnum returns the string values of enum.
+func stringEnum(enum []interface{}) []string {
+	var values []string
+	for _, v := range enum {
+		if s, ok := v.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// int64Enum returns the integer values of enum. JSON numbers are decoded as
+// float64, so only whole numbers are kept, along with the strings that hold
+// them, as in the enum of an integer encoded as a string.
+func int64Enum(enum []interface{}) []int64 {
+	var values []int64
+	for _, v := range enum {
+		if i, ok := defaultInt64(v); ok {
+			values = append(values, i)
+		}
+	}
+	return values
+}
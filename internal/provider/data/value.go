@@ -4,6 +4,7 @@
 package data
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -41,9 +42,20 @@ type Value struct {
 // passed into the Terraform SDK.
 func ToTerraform5Value(v Value, t tftypes.Type) (tftypes.Value, error) {
 	switch {
+	case t.Is(tftypes.DynamicPseudoType):
+		return dynamicToTerraform5Value(v)
 	case t.Is(tftypes.Bool):
 		return tftypes.NewValue(tftypes.Bool, v.Boolean), nil
 	case t.Is(tftypes.String):
+		if v.String == nil && v != (Value{}) {
+			// A dynamic value where the schema has a string, as in data
+			// sources, is held as JSON.
+			b, err := json.Marshal(dynamicToJSON(v))
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			return tftypes.NewValue(tftypes.String, string(b)), nil
+		}
 		return tftypes.NewValue(tftypes.String, v.String), nil
 	case t.Is(tftypes.Number):
 		return tftypes.NewValue(tftypes.Number, v.Number), nil
@@ -90,6 +102,10 @@ func ToTerraform5Value(v Value, t tftypes.Type) (tftypes.Value, error) {
 func FromTerraform5Value(v tftypes.Value) (Value, error) {
 	t := v.Type()
 	switch {
+	case t.Is(tftypes.DynamicPseudoType) && v.IsNull():
+		// Values of dynamic attributes have a type of their own, unless
+		// they are null.
+		return Value{}, nil
 	case t.Is(tftypes.Bool):
 		ret := Value{}
 		err := v.As(&ret.Boolean)
@@ -102,7 +118,9 @@ func FromTerraform5Value(v tftypes.Value) (Value, error) {
 		ret := Value{}
 		err := v.As(&ret.Number)
 		return ret, err
-	case t.Is(tftypes.List{}):
+	case t.Is(tftypes.List{}), t.Is(tftypes.Tuple{}):
+		// Tuples are only found in dynamic values, which don't keep the
+		// difference.
 		return listFromTerraform5Value(v)
 	case t.Is(tftypes.Map{}):
 		return mapFromTerraform5Value(v)
@@ -19,7 +19,7 @@ func aepbaseProviderFactory() map[string]func() (tfprotov6.ProviderServer, error
 	return map[string]func() (tfprotov6.ProviderServer, error){
 		"scaffolding": func() (tfprotov6.ProviderServer, error) {
 			openAPIURL := serverURL + "/openapi.json"
-			gen, err := CreateGeneratedProviderData(context.TODO(), openAPIURL, "")
+			gen, err := CreateGeneratedProviderData(context.TODO(), openAPIURL, "", "")
 			if err != nil {
 				return nil, fmt.Errorf("unable to create generated data from %s: %v", openAPIURL, err)
 			}
@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultLogBodyMaxBytes = 4096
+
+	redacted = "REDACTED"
+)
+
+// defaultRedactHeaders are always redacted, on top of log_redact_headers.
+var defaultRedactHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+// defaultRedactFields are always redacted, on top of log_redact_fields.
+var defaultRedactFields = []string{
+	"password",
+	"secret",
+	"client_secret",
+	"token",
+	"access_token",
+	"refresh_token",
+	"api_key",
+}
+
+// logTransport logs every exchange with the API as a structured tflog entry.
+//
+// Redacted headers and JSON fields are replaced before anything is logged.
+// Bodies are truncated to maxBodyBytes.
+type logTransport struct {
+	base http.RoundTripper
+
+	// maxBodyBytes is the number of body bytes logged. Bodies aren't logged if
+	// it is 0.
+	maxBodyBytes int
+	// redactHeaders holds canonical header names.
+	redactHeaders map[string]bool
+	// redactFields holds lower case JSON field names.
+	redactFields map[string]bool
+}
+
+// newLogTransport returns a logTransport around base, configured from the
+// log_body_max_bytes, log_redact_headers and log_redact_fields provider
+// attributes.
+func newLogTransport(base http.RoundTripper, maxBodyBytes types.Int64, redactHeaders []string, redactFields []string) (*logTransport, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	t := &logTransport{
+		base:          base,
+		maxBodyBytes:  defaultLogBodyMaxBytes,
+		redactHeaders: make(map[string]bool),
+		redactFields:  make(map[string]bool),
+	}
+	if !maxBodyBytes.IsNull() {
+		if maxBodyBytes.ValueInt64() < 0 {
+			diags.AddAttributeError(path.Root("log_body_max_bytes"), "Invalid log_body_max_bytes", "log_body_max_bytes must not be negative.")
+		}
+		t.maxBodyBytes = int(maxBodyBytes.ValueInt64())
+	}
+	for _, h := range append(defaultRedactHeaders, redactHeaders...) {
+		t.redactHeaders[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, f := range append(defaultRedactFields, redactFields...) {
+		t.redactFields[strings.ToLower(f)] = true
+	}
+	return t, diags
+}
+
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	ctx := req.Context()
+
+	fields := map[string]interface{}{
+		"method":          req.Method,
+		"url":             req.URL.String(),
+		"request_headers": t.headers(req.Header),
+	}
+	if t.maxBodyBytes > 0 && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, err := io.ReadAll(body)
+			body.Close()
+			if err == nil {
+				fields["request_body"] = t.body(b)
+			}
+		}
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	fields["latency_ms"] = time.Since(start).Milliseconds()
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Debug(ctx, fmt.Sprintf("%s %s failed", req.Method, req.URL), fields)
+		return resp, err
+	}
+
+	fields["status"] = resp.StatusCode
+	fields["response_headers"] = t.headers(resp.Header)
+	if t.maxBodyBytes > 0 && resp.Body != nil {
+		b, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		// Hand the caller a body that still reads from the start.
+		resp.Body = io.NopCloser(bytes.NewReader(b))
+		if readErr != nil {
+			return resp, readErr
+		}
+		fields["response_body"] = t.body(b)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("%s %s returned %s", req.Method, req.URL, resp.Status), fields)
+	return resp, nil
+}
+
+// headers returns the headers with redacted values replaced.
+func (t *logTransport) headers(h http.Header) map[string]string {
+	result := make(map[string]string, len(h))
+	for k, v := range h {
+		if t.redactHeaders[http.CanonicalHeaderKey(k)] {
+			result[k] = redacted
+			continue
+		}
+		result[k] = strings.Join(v, ", ")
+	}
+	return result
+}
+
+// body returns the body with redacted JSON fields replaced, truncated to
+// maxBodyBytes. Bodies that aren't JSON are only truncated.
+func (t *logTransport) body(b []byte) string {
+	var parsed interface{}
+	if err := decodeJSON(b, &parsed); err == nil {
+		if redactedJSON, err := json.Marshal(t.redactJSON(parsed)); err == nil {
+			b = redactedJSON
+		}
+	}
+	if len(b) <= t.maxBodyBytes {
+		return string(b)
+	}
+	return fmt.Sprintf("%s... (%d more bytes)", b[:t.maxBodyBytes], len(b)-t.maxBodyBytes)
+}
+
+func (t *logTransport) redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if t.redactFields[strings.ToLower(key)] {
+				v[key] = redacted
+			} else {
+				v[key] = t.redactJSON(value)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, value := range v {
+			v[i] = t.redactJSON(value)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestLogTransportRedaction(t *testing.T) {
+	tr, diags := newLogTransport(nil, types.Int64Value(96), []string{"x-custom-secret"}, []string{"ssn"})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	t.Run("headers", func(t *testing.T) {
+		got := tr.headers(http.Header{
+			"Authorization":   {"Bearer abc"},
+			"X-Custom-Secret": {"abc"},
+			"Content-Type":    {"application/json"},
+		})
+		want := map[string]string{
+			"Authorization":   redacted,
+			"X-Custom-Secret": redacted,
+			"Content-Type":    "application/json",
+		}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("headers() mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	tests := map[string]struct {
+		body string
+		want string
+	}{
+		"nested fields": {
+			body: `{"name": "a", "Password": "p", "owner": {"ssn": "123"}, "keys": [{"api_key": "k"}]}`,
+			want: `{"Password":"REDACTED","keys":[{"api_key":"REDACTED"}],"name":"a","owner":{"ssn":"REDACTED"}}`,
+		},
+		"not json": {
+			body: `plain text`,
+			want: `plain text`,
+		},
+		"truncated": {
+			body: `{"description": "0123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789"}`,
+			want: `{"description":"01234567890123456789012345678901234567890123456789012345678901234567890123456789... (22 more bytes)`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tr.body([]byte(tt.body)); got != tt.want {
+				t.Errorf("body() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+)
+
+type responsesKey struct{}
+
+// responses holds the JSON bodies of the successful responses to the requests
+// made with a context returned by withResponses, in order.
+type responses struct {
+	mu     sync.Mutex
+	bodies [][]byte
+}
+
+// withResponses returns a context whose requests have their responses kept in
+// the returned responses.
+func withResponses(ctx context.Context) (context.Context, *responses) {
+	r := &responses{}
+	return context.WithValue(ctx, responsesKey{}, r), r
+}
+
+// responseTransport keeps the bodies of responses for requests whose context
+// came from withResponses. Other requests pass through untouched.
+//
+// The client decodes responses into float64 numbers, which can't hold every
+// int64, so the kept bodies are decoded again with exact numbers.
+type responseTransport struct {
+	base http.RoundTripper
+}
+
+func (t *responseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	r, ok := req.Context().Value(responsesKey{}).(*responses)
+	if err != nil || !ok || resp.Body == nil || resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, err
+	}
+
+	b, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	// Hand the caller a body that still reads from the start.
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	if readErr != nil {
+		return resp, readErr
+	}
+	r.mu.Lock()
+	r.bodies = append(r.bodies, b)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// resource returns m, a resource the client decoded from the last response,
+// with its numbers as json.Number. m is returned as-is if the last response
+// isn't that resource, such as when an operation was polled.
+func (r *responses) resource(m map[string]interface{}) map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.bodies) == 0 {
+		return m
+	}
+	var exact map[string]interface{}
+	if err := decodeJSON(r.bodies[len(r.bodies)-1], &exact); err != nil || !sameFields(exact, m) {
+		return m
+	}
+	return exact
+}
+
+// list returns l, the resources the client listed from every response, with
+// their numbers as json.Number. l is returned as-is if the responses don't
+// hold those resources.
+func (r *responses) list(l []map[string]interface{}) []map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var exact []map[string]interface{}
+	for _, b := range r.bodies {
+		var page struct {
+			Results []map[string]interface{} `json:"results"`
+		}
+		if err := decodeJSON(b, &page); err != nil {
+			return l
+		}
+		exact = append(exact, page.Results...)
+	}
+	if len(exact) != len(l) {
+		return l
+	}
+	for i := range exact {
+		if !sameFields(exact[i], l[i]) {
+			return l
+		}
+	}
+	return exact
+}
+
+// decodeJSON decodes b into v, with numbers as json.Number.
+func decodeJSON(b []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+func sameFields(a, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
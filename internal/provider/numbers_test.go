@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestResponseTransport(t *testing.T) {
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder("GET", "http://localhost:8081/publishers/1",
+		httpmock.NewStringResponder(200, `{"path": "publishers/1", "id": 9007199254740993}`))
+	mock.RegisterResponder("GET", "http://localhost:8081/publishers",
+		httpmock.NewStringResponder(200, `{"results": [{"path": "publishers/1", "size": 9007199254740993}]}`))
+	c := &http.Client{Transport: &responseTransport{base: mock}}
+
+	get := func(ctx context.Context, url string) []byte {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return b
+	}
+
+	// The caller still reads the whole body, as a client that loses
+	// precision would.
+	ctx, responses := withResponses(context.Background())
+	var lossy map[string]interface{}
+	if err := json.Unmarshal(get(ctx, "http://localhost:8081/publishers/1"), &lossy); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]interface{}{"path": "publishers/1", "id": json.Number("9007199254740993")}
+	if got := responses.resource(lossy); !reflect.DeepEqual(got, want) {
+		t.Errorf("resource() = %v, want %v", got, want)
+	}
+
+	// A response that isn't the resource, such as an operation, is ignored.
+	other := map[string]interface{}{"path": "publishers/1", "name": "x"}
+	if got := responses.resource(other); !reflect.DeepEqual(got, other) {
+		t.Errorf("resource() = %v, want %v", got, other)
+	}
+
+	ctx, responses = withResponses(context.Background())
+	get(ctx, "http://localhost:8081/publishers")
+	lossyList := []map[string]interface{}{{"path": "publishers/1", "size": float64(9007199254740992)}}
+	wantList := []map[string]interface{}{{"path": "publishers/1", "size": json.Number("9007199254740993")}}
+	if got := responses.list(lossyList); !reflect.DeepEqual(got, wantList) {
+		t.Errorf("list() = %v, want %v", got, wantList)
+	}
+
+	// Requests without responses aren't kept.
+	get(context.Background(), "http://localhost:8081/publishers/1")
+	if len(responses.bodies) != 1 {
+		t.Errorf("kept %d responses, want 1", len(responses.bodies))
+	}
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// tokenExpiryDelta is how long before its expiry a token is refreshed.
+// Refreshing early keeps a token from expiring while a request is in flight.
+const tokenExpiryDelta = time.Minute
+
+// OAuth2Model describes the oauth2 block of the provider configuration.
+type OAuth2Model struct {
+	TokenURL     types.String `tfsdk:"token_url"`
+	ClientID     types.String `tfsdk:"client_id"`
+	ClientSecret types.String `tfsdk:"client_secret"`
+	Scopes       []string     `tfsdk:"scopes"`
+	Audience     types.String `tfsdk:"audience"`
+}
+
+func oauth2Block() schema.Block {
+	return schema.SingleNestedBlock{
+		Description: "Authenticate with the OAuth2 client credentials flow. Tokens are minted and refreshed automatically and sent as a bearer token with every request.",
+		Attributes: map[string]schema.Attribute{
+			"token_url": schema.StringAttribute{
+				Description: "The URL of the token endpoint.",
+				Required:    true,
+			},
+			"client_id": schema.StringAttribute{
+				Description: "The OAuth2 client ID.",
+				Required:    true,
+			},
+			"client_secret": schema.StringAttribute{
+				Description: "The OAuth2 client secret.",
+				Required:    true,
+				Sensitive:   true,
+			},
+			"scopes": schema.ListAttribute{
+				Description: "The scopes to request.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"audience": schema.StringAttribute{
+				Description: "The audience to request the token for.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// TokenSource returns a token source that fetches tokens from the token
+// endpoint and caches them until shortly before they expire.
+//
+// Token requests are made with the http.Client carried by ctx (see
+// oauth2.HTTPClient), or http.DefaultClient if there is none.
+func (m *OAuth2Model) TokenSource(ctx context.Context) oauth2.TokenSource {
+	cfg := &clientcredentials.Config{
+		ClientID:     m.ClientID.ValueString(),
+		ClientSecret: m.ClientSecret.ValueString(),
+		TokenURL:     m.TokenURL.ValueString(),
+		Scopes:       m.Scopes,
+	}
+	if m.Audience.ValueString() != "" {
+		cfg.EndpointParams = map[string][]string{
+			"audience": {m.Audience.ValueString()},
+		}
+	}
+
+	// cfg.TokenSource() refreshes only ten seconds before expiry, so wrap the
+	// uncached source with our own expiry delta instead.
+	return oauth2.ReuseTokenSourceWithExpiry(nil, clientCredentialsSource{ctx: ctx, cfg: cfg}, tokenExpiryDelta)
+}
+
+// Transport wraps base so that every request carries a fresh bearer token.
+func (m *OAuth2Model) Transport(ctx context.Context, base http.RoundTripper) http.RoundTripper {
+	return &oauth2.Transport{
+		Source: m.TokenSource(ctx),
+		Base:   base,
+	}
+}
+
+type clientCredentialsSource struct {
+	ctx context.Context
+	cfg *clientcredentials.Config
+}
+
+func (s clientCredentialsSource) Token() (*oauth2.Token, error) {
+	return s.cfg.Token(s.ctx)
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/jarcoal/httpmock"
+)
+
+func TestOAuth2Transport(t *testing.T) {
+	tests := map[string]struct {
+		expiresIn int
+		want      []string
+	}{
+		"reuses valid token": {
+			expiresIn: 3600,
+			want:      []string{"Bearer token-1", "Bearer token-1"},
+		},
+		"refreshes before expiry": {
+			expiresIn: 30,
+			want:      []string{"Bearer token-1", "Bearer token-2"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tokens := 0
+			httpmock.RegisterResponder("POST", "http://localhost:8081/token",
+				func(req *http.Request) (*http.Response, error) {
+					if err := req.ParseForm(); err != nil {
+						return httpmock.NewStringResponse(400, ""), err
+					}
+					if req.PostForm.Get("audience") != "my-audience" {
+						return httpmock.NewStringResponse(400, "missing audience"), nil
+					}
+					tokens += 1
+					return httpmock.NewJsonResponse(200, map[string]interface{}{
+						"access_token": fmt.Sprintf("token-%d", tokens),
+						"token_type":   "Bearer",
+						"expires_in":   tt.expiresIn,
+					})
+				},
+			)
+			httpmock.RegisterResponder("GET", "http://localhost:8081/publishers/1",
+				func(req *http.Request) (*http.Response, error) {
+					return httpmock.NewStringResponse(200, req.Header.Get("Authorization")), nil
+				},
+			)
+
+			m := &OAuth2Model{
+				TokenURL:     types.StringValue("http://localhost:8081/token"),
+				ClientID:     types.StringValue("my-client"),
+				ClientSecret: types.StringValue("my-secret"),
+				Audience:     types.StringValue("my-audience"),
+			}
+			c := &http.Client{Transport: m.Transport(context.Background(), nil)}
+
+			for i, want := range tt.want {
+				resp, err := c.Get("http://localhost:8081/publishers/1")
+				if err != nil {
+					t.Fatalf("request %d: unexpected error: %v", i, err)
+				}
+				got, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if err != nil {
+					t.Fatalf("request %d: unexpected error reading body: %v", i, err)
+				}
+				if string(got) != want {
+					t.Errorf("request %d: Authorization = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
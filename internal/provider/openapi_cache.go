@@ -0,0 +1,249 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aep-dev/aep-lib-go/pkg/openapi"
+	"github.com/aep-dev/terraform-provider-aep/internal/provider/data"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// cacheEntry is the metadata stored next to a cached spec.
+type cacheEntry struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	// Ext is the extension of the cached spec, which tells the parser its
+	// format. Entries written before it was recorded use the extension of
+	// the URL.
+	Ext string `json:"ext,omitempty"`
+}
+
+// fetchOpenAPI fetches the OpenAPI spec at path. It is returned both as parsed
+// by aep-lib-go and as a data.Spec, which keeps the schema keywords that
+// aep-lib-go drops.
+//
+// Specs served over HTTP(S) are downloaded with c and cached in cacheDir, if
+// set. Local files bypass the cache entirely.
+func fetchOpenAPI(ctx context.Context, c *http.Client, path string, cacheDir string) (*openapi.OpenAPI, *data.Spec, error) {
+	if !isRemote(path) {
+		return readOpenAPI(path)
+	}
+	if cacheDir == "" {
+		// Without a cache, download into a throwaway directory instead.
+		tmp, err := os.MkdirTemp("", "aep-openapi-")
+		if err != nil {
+			return nil, nil, err
+		}
+		defer os.RemoveAll(tmp)
+		cacheDir = tmp
+	}
+	cachedPath, err := cacheOpenAPI(ctx, c, path, cacheDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	return readOpenAPI(cachedPath)
+}
+
+// readOpenAPI reads the OpenAPI spec in the local file at path.
+func readOpenAPI(path string) (*openapi.OpenAPI, *data.Spec, error) {
+	oas, err := openapi.FetchOpenAPI(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	spec, err := data.ParseSpec(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return oas, spec, nil
+}
+
+// cacheOpenAPI makes sure the spec at specURL is cached in cacheDir and
+// returns the path of the cached copy.
+//
+// A cached copy is revalidated with If-None-Match / If-Modified-Since. If the
+// spec host can't be reached or returns an error, the cached copy is used as-is.
+func cacheOpenAPI(ctx context.Context, c *http.Client, specURL string, cacheDir string) (string, error) {
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return "", fmt.Errorf("unable to create OpenAPI cache directory %s: %w", cacheDir, err)
+	}
+
+	key, entryPath := cachePaths(specURL, cacheDir)
+
+	var entry *cacheEntry
+	cached, err := readCacheEntry(entryPath)
+	if err != nil {
+		tflog.Warn(ctx, fmt.Sprintf("ignoring unreadable OpenAPI cache entry %s: %v", entryPath, err))
+		cached = &cacheEntry{}
+	}
+	if cached.Ext == "" {
+		cached.Ext = urlExt(specURL)
+	}
+	specPath := key + cached.Ext
+	if _, err := os.Stat(specPath); err == nil {
+		entry = cached
+	}
+
+	// fallback is used when the spec can't be fetched.
+	fallback := func(cause error) (string, error) {
+		if entry == nil {
+			return "", fmt.Errorf("unable to fetch OpenAPI spec %s: %w", specURL, cause)
+		}
+		tflog.Warn(ctx, fmt.Sprintf("unable to fetch OpenAPI spec %s, using cached copy %s: %v", specURL, specPath, cause))
+		return specPath, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, specURL, nil)
+	if err != nil {
+		return "", err
+	}
+	if entry != nil {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return fallback(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		tflog.Debug(ctx, fmt.Sprintf("OpenAPI spec %s not modified, using cached copy %s", specURL, specPath))
+		return specPath, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fallback(fmt.Errorf("unexpected status %s", resp.Status))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fallback(err)
+	}
+	ext := specExt(specURL, resp.Header.Get("Content-Type"), body)
+	specPath = key + ext
+	if err := writeFileAtomic(specPath, body); err != nil {
+		return "", err
+	}
+
+	entry = &cacheEntry{
+		URL:          specURL,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Ext:          ext,
+	}
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	if err := writeFileAtomic(entryPath, entryJSON); err != nil {
+		return "", err
+	}
+	return specPath, nil
+}
+
+// cachePaths returns the path of the cached spec without its extension, and
+// the path of its metadata. Entries are keyed by a hash of the URL.
+func cachePaths(specURL string, cacheDir string) (string, string) {
+	sum := sha256.Sum256([]byte(specURL))
+	key := filepath.Join(cacheDir, hex.EncodeToString(sum[:]))
+	return key, key + ".entry.json"
+}
+
+// specExt returns the extension the spec fetched from specURL is cached with,
+// so that it is parsed the same way as the original: the extension of the URL,
+// or else the format of the Content-Type, or else of the body itself.
+func specExt(specURL string, contentType string, body []byte) string {
+	if ext := filepath.Ext(urlPath(specURL)); ext != "" {
+		return ext
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch {
+		case strings.Contains(mediaType, "yaml"):
+			return ".yaml"
+		case strings.Contains(mediaType, "json"):
+			return ".json"
+		}
+	}
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] != '{' {
+		return ".yaml"
+	}
+	return ".json"
+}
+
+// urlExt returns the extension of the path of specURL, or ".json" if it has
+// none.
+func urlExt(specURL string) string {
+	if ext := filepath.Ext(urlPath(specURL)); ext != "" {
+		return ext
+	}
+	return ".json"
+}
+
+func urlPath(specURL string) string {
+	u, err := url.Parse(specURL)
+	if err != nil {
+		return ""
+	}
+	return u.Path
+}
+
+func readCacheEntry(path string) (*cacheEntry, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// The spec was cached without any validators.
+		return &cacheEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entry := &cacheEntry{}
+	if err := json.Unmarshal(b, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// writeFileAtomic writes data to path through a temporary file, so that
+// concurrently starting plugins never read a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func isRemote(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestCacheOpenAPI(t *testing.T) {
+	const specURL = "http://localhost:8081/openapi.json"
+	const spec = `{"openapi": "3.1.0"}`
+
+	mock := httpmock.NewMockTransport()
+	c := &http.Client{Transport: mock}
+	cacheDir := t.TempDir()
+
+	// An empty cache with an unreachable host is an error.
+	mock.RegisterResponder("GET", specURL, httpmock.NewErrorResponder(errors.New("connection refused")))
+	if _, err := cacheOpenAPI(context.TODO(), c, specURL, cacheDir); err == nil {
+		t.Fatalf("expected error when fetching uncached spec from unreachable host")
+	}
+
+	// The first successful fetch populates the cache.
+	mock.RegisterResponder("GET", specURL, func(req *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(200, spec)
+		resp.Header.Set("ETag", `"v1"`)
+		return resp, nil
+	})
+	path, err := cacheOpenAPI(context.TODO(), c, specURL, cacheDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkCachedSpec(t, path, spec)
+
+	// Later fetches revalidate the cached copy.
+	mock.RegisterResponder("GET", specURL, func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("If-None-Match") != `"v1"` {
+			return httpmock.NewStringResponse(500, "expected If-None-Match header"), nil
+		}
+		return httpmock.NewStringResponse(304, ""), nil
+	})
+	path, err = cacheOpenAPI(context.TODO(), c, specURL, cacheDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkCachedSpec(t, path, spec)
+
+	// The cached copy is used when the host is unreachable.
+	mock.RegisterResponder("GET", specURL, httpmock.NewErrorResponder(errors.New("connection refused")))
+	path, err = cacheOpenAPI(context.TODO(), c, specURL, cacheDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkCachedSpec(t, path, spec)
+
+	// The cached copy is used when the host returns an error.
+	mock.RegisterResponder("GET", specURL, httpmock.NewStringResponder(503, ""))
+	path, err = cacheOpenAPI(context.TODO(), c, specURL, cacheDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkCachedSpec(t, path, spec)
+}
+
+func checkCachedSpec(t *testing.T, path string, want string) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read cached spec: %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("cached spec = %q, want %q", got, want)
+	}
+}
+
+func TestCacheOpenAPIFormat(t *testing.T) {
+	tests := map[string]struct {
+		url         string
+		contentType string
+		body        string
+		wantExt     string
+	}{
+		"url extension":       {url: "http://localhost:8081/openapi.yaml", contentType: "application/json", body: "openapi: 3.1.0", wantExt: ".yaml"},
+		"yaml content type":   {url: "http://localhost:8081/openapi", contentType: "application/yaml; charset=utf-8", body: "openapi: 3.1.0", wantExt: ".yaml"},
+		"json content type":   {url: "http://localhost:8081/openapi", contentType: "application/json", body: `{"openapi": "3.1.0"}`, wantExt: ".json"},
+		"yaml body":           {url: "http://localhost:8081/openapi", contentType: "text/plain", body: "openapi: 3.1.0", wantExt: ".yaml"},
+		"json body":           {url: "http://localhost:8081/openapi", body: ` {"openapi": "3.1.0"}`, wantExt: ".json"},
+		"x-yaml content type": {url: "http://localhost:8081/spec?format=yaml", contentType: "application/x-yaml", body: "openapi: 3.1.0", wantExt: ".yaml"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mock := httpmock.NewMockTransport()
+			c := &http.Client{Transport: mock}
+			cacheDir := t.TempDir()
+
+			mock.RegisterResponder("GET", tt.url, func(req *http.Request) (*http.Response, error) {
+				resp := httpmock.NewStringResponse(200, tt.body)
+				resp.Header.Set("Content-Type", tt.contentType)
+				resp.Header.Set("ETag", `"v1"`)
+				return resp, nil
+			})
+			path, err := cacheOpenAPI(context.TODO(), c, tt.url, cacheDir)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if filepath.Ext(path) != tt.wantExt {
+				t.Errorf("cached spec %s, want extension %s", path, tt.wantExt)
+			}
+			checkCachedSpec(t, path, tt.body)
+
+			// The revalidated copy keeps its format.
+			mock.RegisterResponder("GET", tt.url, httpmock.NewStringResponder(304, ""))
+			revalidated, err := cacheOpenAPI(context.TODO(), c, tt.url, cacheDir)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if revalidated != path {
+				t.Errorf("revalidated spec %s, want %s", revalidated, path)
+			}
+		})
+	}
+}
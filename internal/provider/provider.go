@@ -5,15 +5,22 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
 
 	"github.com/aep-dev/aep-lib-go/pkg/client"
 	"github.com/aep-dev/terraform-provider-aep/config"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/oauth2"
 )
 
 // Ensure ScaffoldingProvider satisfies various provider interfaces.
@@ -37,7 +44,21 @@ type ScaffoldingProvider struct {
 
 // ScaffoldingProviderModel describes the provider data model.
 type ScaffoldingProviderModel struct {
-	Headers map[string]string `tfsdk:"headers"`
+	Headers               map[string]string `tfsdk:"headers"`
+	Endpoint              types.String      `tfsdk:"endpoint"`
+	RetryMaxAttempts      types.Int64       `tfsdk:"retry_max_attempts"`
+	RetryMaxWait          types.String      `tfsdk:"retry_max_wait"`
+	RequestsPerSecond     types.Float64     `tfsdk:"requests_per_second"`
+	MaxConcurrentRequests types.Int64       `tfsdk:"max_concurrent_requests"`
+	CACertPEM             types.String      `tfsdk:"ca_cert_pem"`
+	CACertFile            types.String      `tfsdk:"ca_cert_file"`
+	ClientCert            types.String      `tfsdk:"client_cert"`
+	ClientKey             types.String      `tfsdk:"client_key"`
+	InsecureSkipVerify    types.Bool        `tfsdk:"insecure_skip_verify"`
+	LogBodyMaxBytes       types.Int64       `tfsdk:"log_body_max_bytes"`
+	LogRedactHeaders      []string          `tfsdk:"log_redact_headers"`
+	LogRedactFields       []string          `tfsdk:"log_redact_fields"`
+	OAuth2                *OAuth2Model      `tfsdk:"oauth2"`
 }
 
 func (p *ScaffoldingProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -53,6 +74,64 @@ func (p *ScaffoldingProvider) Schema(ctx context.Context, req provider.SchemaReq
 				Optional:    true,
 				ElementType: types.StringType,
 			},
+			"endpoint": schema.StringAttribute{
+				Description: "The URL of the API server. Overrides the server URL from the OpenAPI spec. It can only be set if the provider has a single OpenAPI spec; with several, set server_url on each spec in AEP_OPENAPI_SPECS instead. Defaults to the AEP_ENDPOINT environment variable.",
+				Optional:    true,
+			},
+			"retry_max_attempts": schema.Int64Attribute{
+				Description: fmt.Sprintf("The maximum number of attempts for a request that fails with a transient error (429, 502, 503 or a reset connection), including the first. Only idempotent requests are retried. Set to 1 to disable retries. Defaults to %d.", defaultRetryMaxAttempts),
+				Optional:    true,
+			},
+			"retry_max_wait": schema.StringAttribute{
+				Description: fmt.Sprintf("The maximum time to wait between two attempts, as a duration such as \"30s\". If the server asks for a longer wait with Retry-After, the request fails instead. Defaults to %q.", defaultRetryMaxWait),
+				Optional:    true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Description: "The maximum number of requests per second sent by this provider instance, across all of its resources and data sources. Unlimited by default.",
+				Optional:    true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Description: "The maximum number of requests this provider instance has in flight at once, across all of its resources and data sources. Unlimited by default.",
+				Optional:    true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Description: "PEM-encoded CA certificates to trust in addition to the system roots. Defaults to the AEP_CA_CERT_PEM environment variable.",
+				Optional:    true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Description: "Path to a file of PEM-encoded CA certificates to trust in addition to the system roots. Defaults to the AEP_CA_CERT_FILE environment variable.",
+				Optional:    true,
+			},
+			"client_cert": schema.StringAttribute{
+				Description: "PEM-encoded client certificate for mutual TLS. Requires client_key. Defaults to the AEP_CLIENT_CERT environment variable.",
+				Optional:    true,
+			},
+			"client_key": schema.StringAttribute{
+				Description: "PEM-encoded private key of client_cert. Defaults to the AEP_CLIENT_KEY environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: "Skip verification of the server's certificate. Only use this for development. Defaults to the AEP_INSECURE_SKIP_VERIFY environment variable.",
+				Optional:    true,
+			},
+			"log_body_max_bytes": schema.Int64Attribute{
+				Description: fmt.Sprintf("The number of bytes of each request and response body included in debug logs. Set to 0 to leave bodies out. Defaults to %d.", defaultLogBodyMaxBytes),
+				Optional:    true,
+			},
+			"log_redact_headers": schema.ListAttribute{
+				Description: fmt.Sprintf("Headers whose values are redacted in debug logs, in addition to %s.", strings.Join(defaultRedactHeaders, ", ")),
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"log_redact_fields": schema.ListAttribute{
+				Description: fmt.Sprintf("JSON fields whose values are redacted from bodies in debug logs, at any depth and case-insensitively, in addition to %s.", strings.Join(defaultRedactFields, ", ")),
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"oauth2": oauth2Block(),
 		},
 	}
 }
@@ -66,27 +145,116 @@ func (p *ScaffoldingProvider) Configure(ctx context.Context, req provider.Config
 		return
 	}
 
+	if p.generator != nil {
+		resp.Diagnostics.Append(p.generator.diagnostics...)
+	}
+
+	settings, err := tlsSettingsFromEnv()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid TLS Configuration", err.Error())
+		return
+	}
+	// A nil transport falls back to http.DefaultTransport.
+	tlsTransport, err := settings.merge(data).transport()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid TLS Configuration", err.Error())
+		return
+	}
+
+	// Logging sits right above TLS, so that it sees the final headers,
+	// including the ones set by OAuth2.
+	var transport http.RoundTripper
+	transport, diags := newLogTransport(tlsTransport, data.LogBodyMaxBytes, data.LogRedactHeaders, data.LogRedactFields)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.OAuth2 != nil {
+		// Tokens are refreshed long after Configure returns, so they can't be
+		// tied to its context. They are fetched with the same TLS settings,
+		// but never logged.
+		tokenCtx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: tlsTransport})
+		transport = data.OAuth2.Transport(tokenCtx, transport)
+	}
+	// Limits sit inside retries, so that every attempt counts against them.
+	transport, diags = newLimitTransport(transport, data.RequestsPerSecond, data.MaxConcurrentRequests)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// Retries wrap everything else, so that every attempt is authenticated
+	// with a fresh token.
+	transport, diags = newRetryTransport(transport, data.RetryMaxAttempts, data.RetryMaxWait)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// Responses are kept after retries, so that only the final one is.
+	transport = &responseTransport{base: transport}
+
+	c := newClient(p.client, &http.Client{Transport: transport})
 	for k, v := range data.Headers {
-		p.client.Headers[k] = v
+		c.Headers[k] = v
+	}
+
+	endpoint, diags := p.endpoint(data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	providerData := &ConfiguredProviderData{
+		client:   c,
+		endpoint: endpoint,
 	}
 
 	// Example client configuration for data sources and resources
-	resp.DataSourceData = p.client
-	resp.ResourceData = p.client
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+}
+
+// endpoint returns the URL that replaces the server URL of the spec, from the
+// endpoint attribute or AEP_ENDPOINT. A single URL can't stand in for the
+// servers of several specs, so it is rejected if there are more than one.
+func (p *ScaffoldingProvider) endpoint(data ScaffoldingProviderModel) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	endpoint := data.Endpoint.ValueString()
+	if endpoint == "" {
+		endpoint = os.Getenv("AEP_ENDPOINT")
+	}
+	if endpoint != "" && p.generator != nil && p.generator.specs > 1 {
+		diags.AddAttributeError(path.Root("endpoint"), "Invalid endpoint",
+			fmt.Sprintf("endpoint replaces the server URL of every OpenAPI spec, so it can't be set when the provider has %d specs. Set server_url on each spec in AEP_OPENAPI_SPECS instead.", p.generator.specs))
+		return "", diags
+	}
+	return strings.TrimSuffix(endpoint, "/"), diags
+}
+
+// newClient returns a client that sends requests through httpClient and
+// otherwise behaves like template.
+func newClient(template *client.Client, httpClient *http.Client) *client.Client {
+	c := client.NewClient(httpClient)
+	c.RequestLoggingFunction = template.RequestLoggingFunction
+	c.ResponseLoggingFunction = template.ResponseLoggingFunction
+	for k, v := range template.Headers {
+		c.Headers[k] = v
+	}
+	return c
 }
 
 func (p *ScaffoldingProvider) Resources(ctx context.Context) []func() resource.Resource {
 	resources := []func() resource.Resource{}
-	for name, resource := range p.generator.api.Resources {
-		resources = append(resources, NewExampleResourceWithResource(resource, p.generator.api, name, p.generator.openapi, p.generator.resources[name]))
+	for name, g := range p.generator.resources {
+		resources = append(resources, NewExampleResourceWithResource(g.resource, g.api, name, g.openapi, g.schema))
 	}
 	return resources
 }
 
 func (p *ScaffoldingProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	resources := []func() datasource.DataSource{}
-	for name, resource := range p.generator.api.Resources {
-		resources = append(resources, NewDataSourceWithResource(resource, p.generator.api, name, p.generator.openapi, p.generator.resources[name]))
+	for name, g := range p.generator.resources {
+		resources = append(resources, NewDataSourceWithResource(g.resource, g.api, name, g.openapi, g.schema))
 	}
 	return resources
 }
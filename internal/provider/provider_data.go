@@ -2,49 +2,149 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/aep-dev/aep-lib-go/pkg/api"
+	"github.com/aep-dev/aep-lib-go/pkg/client"
 	"github.com/aep-dev/aep-lib-go/pkg/openapi"
+	"github.com/aep-dev/terraform-provider-aep/config"
 	"github.com/aep-dev/terraform-provider-aep/internal/provider/data"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 )
 
 type GeneratedProviderData struct {
-	client  *http.Client
-	api     *api.API
-	openapi *openapi.OpenAPI
+	client *http.Client
 
-	resources map[string]*data.ResourceSchema
+	resources map[string]*GeneratedResource
+
+	// specs is the number of specs the resources were generated from.
+	specs int
+
+	// diagnostics holds the warnings from generating the resource schemas.
+	// They are reported when the provider is configured.
+	diagnostics diag.Diagnostics
+}
+
+// GeneratedResource is a resource generated from one of the provider's specs.
+type GeneratedResource struct {
+	resource *api.Resource
+	api      *api.API
+	openapi  *openapi.OpenAPI
+	schema   *data.ResourceSchema
+
+	// spec is the path of the spec the resource was generated from.
+	spec string
+}
+
+// ConfiguredProviderData is created by the provider's Configure method and
+// handed to every resource and data source.
+type ConfiguredProviderData struct {
+	client *client.Client
+
+	// endpoint replaces the server URL from the OpenAPI spec, if set.
+	endpoint string
 }
 
 func (p *GeneratedProviderData) Resource(name string) {
 
 }
 
-func CreateGeneratedProviderData(ctx context.Context, path string, pathPrefix string) (*GeneratedProviderData, error) {
-	oas, err := openapi.FetchOpenAPI(path)
+// CreateGeneratedProviderData fetches the OpenAPI spec at path and generates
+// the resource schemas from it. If cacheDir is set, specs fetched over HTTP(S)
+// are cached there.
+//
+// The TLS settings from the environment apply to the fetch.
+func CreateGeneratedProviderData(ctx context.Context, path string, pathPrefix string, cacheDir string) (*GeneratedProviderData, error) {
+	return CreateGeneratedProviderDataFromSpecs(ctx, []config.OpenAPISpec{{Path: path, PathPrefix: pathPrefix}}, config.GeneratorOptions{
+		CacheDir:       cacheDir,
+		RecursionDepth: data.DefaultRecursionDepth,
+	})
+}
+
+// CreateGeneratedProviderDataFromSpecs fetches every spec and merges the
+// resources generated from them. Each resource is managed on the server of the
+// spec it came from.
+//
+// Two specs may not define a resource with the same name. The schemas are
+// generated with options, usually taken from config.ProviderConfig.
+func CreateGeneratedProviderDataFromSpecs(ctx context.Context, specs []config.OpenAPISpec, options config.GeneratorOptions) (*GeneratedProviderData, error) {
+	settings, err := tlsSettingsFromEnv()
 	if err != nil {
 		return nil, err
 	}
-
-	a, err := api.GetAPI(oas, "", pathPrefix)
+	transport, err := settings.transport()
 	if err != nil {
 		return nil, err
 	}
+	httpClient := &http.Client{Transport: transport}
 
-	resources := make(map[string]*data.ResourceSchema)
-	for name, resource := range a.Resources {
-		resSchema, err := data.NewResourceSchema(context.Background(), resource, oas)
+	resources := make(map[string]*GeneratedResource)
+	var collisions []string
+	for _, spec := range specs {
+		oas, schemas, err := fetchOpenAPI(ctx, httpClient, spec.Path, options.CacheDir)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("unable to load OpenAPI spec %s: %w", spec.Path, err)
 		}
-		resources[name] = resSchema
+		schemas.RecursionDepth = options.RecursionDepth
+		schemas.DynamicValues = options.DynamicValues
+
+		a, err := api.GetAPI(oas, spec.ServerURL, spec.PathPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read resources from OpenAPI spec %s: %w", spec.Path, err)
+		}
+
+		for name, resource := range a.Resources {
+			if existing, ok := resources[name]; ok {
+				collisions = append(collisions, fmt.Sprintf("resource %q is defined by both %s and %s", name, existing.spec, spec.Path))
+				continue
+			}
+			resSchema, err := data.NewResourceSchemaFromSpec(context.Background(), resource, schemas)
+			if err != nil {
+				return nil, err
+			}
+			resources[name] = &GeneratedResource{
+				resource: resource,
+				api:      a,
+				openapi:  oas,
+				schema:   resSchema,
+				spec:     spec.Path,
+			}
+		}
+	}
+	if len(collisions) > 0 {
+		sort.Strings(collisions)
+		return nil, fmt.Errorf("OpenAPI specs define conflicting resources:\n  %s", strings.Join(collisions, "\n  "))
+	}
+
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	// Warnings with the same summary, such as every renamed attribute, are
+	// listed in a single diagnostic.
+	var summaries []string
+	details := make(map[string][]string)
+	for _, name := range names {
+		for _, d := range resources[name].schema.Diagnostics {
+			if _, ok := details[d.Summary()]; !ok {
+				summaries = append(summaries, d.Summary())
+			}
+			details[d.Summary()] = append(details[d.Summary()], fmt.Sprintf("Resource %q: %s", name, d.Detail()))
+		}
+	}
+	var diags diag.Diagnostics
+	for _, summary := range summaries {
+		diags.AddWarning(summary, strings.Join(details[summary], "\n"))
 	}
 
 	return &GeneratedProviderData{
-		client:    http.DefaultClient,
-		api:       a,
-		openapi:   oas,
-		resources: resources,
+		client:      http.DefaultClient,
+		resources:   resources,
+		specs:       len(specs),
+		diagnostics: diags,
 	}, nil
 }
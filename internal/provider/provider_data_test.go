@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aep-dev/terraform-provider-aep/config"
+	"github.com/aep-dev/terraform-provider-aep/internal/provider/data"
+)
+
+func TestCreateGeneratedProviderDataFromSpecsServerURL(t *testing.T) {
+	gen, err := CreateGeneratedProviderDataFromSpecs(context.Background(), []config.OpenAPISpec{
+		{Path: "testdata/oas.yaml", ServerURL: "http://localhost:9000"},
+	}, config.GeneratorOptions{RecursionDepth: data.DefaultRecursionDepth})
+	if err != nil {
+		t.Fatalf("CreateGeneratedProviderDataFromSpecs() error = %v", err)
+	}
+	if len(gen.resources) == 0 {
+		t.Fatal("no resources were generated")
+	}
+	for name, g := range gen.resources {
+		if g.api.ServerURL != "http://localhost:9000" {
+			t.Errorf("resource %s has server URL %q, want %q", name, g.api.ServerURL, "http://localhost:9000")
+		}
+		if g.spec != "testdata/oas.yaml" {
+			t.Errorf("resource %s has spec %q, want %q", name, g.spec, "testdata/oas.yaml")
+		}
+	}
+}
+
+func TestCreateGeneratedProviderDataFromSpecsCollision(t *testing.T) {
+	_, err := CreateGeneratedProviderDataFromSpecs(context.Background(), []config.OpenAPISpec{
+		{Path: "testdata/oas.yaml"},
+		{Path: "./testdata/oas.yaml"},
+	}, config.GeneratorOptions{RecursionDepth: data.DefaultRecursionDepth})
+	if err == nil {
+		t.Fatal("CreateGeneratedProviderDataFromSpecs() succeeded, want a collision error")
+	}
+	want := `resource "publisher" is defined by both testdata/oas.yaml and ./testdata/oas.yaml`
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err.Error(), want)
+	}
+}
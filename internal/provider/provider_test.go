@@ -14,6 +14,7 @@ import (
 	"github.com/aep-dev/aep-lib-go/pkg/client"
 	"github.com/aep-dev/terraform-provider-aep/config"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/jarcoal/httpmock"
 )
@@ -24,7 +25,7 @@ import (
 // reattach.
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
 	"scaffolding": func() (tfprotov6.ProviderServer, error) {
-		gen, err := CreateGeneratedProviderData(context.TODO(), "testdata/oas.yaml", "")
+		gen, err := CreateGeneratedProviderData(context.TODO(), "testdata/oas.yaml", "", "")
 		if err != nil {
 			return nil, fmt.Errorf("unable to create generated data %v", err)
 		}
@@ -37,7 +38,7 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 // nolint:unused
 var testAccProtoV6ProviderFactoriesWithRoblox = map[string]func() (tfprotov6.ProviderServer, error){
 	"scaffolding": func() (tfprotov6.ProviderServer, error) {
-		gen, err := CreateGeneratedProviderData(context.TODO(), "https://raw.githubusercontent.com/Roblox/creator-docs/refs/heads/main/content/en-us/reference/cloud/cloud.docs.json", "/cloud/v2")
+		gen, err := CreateGeneratedProviderData(context.TODO(), "https://raw.githubusercontent.com/Roblox/creator-docs/refs/heads/main/content/en-us/reference/cloud/cloud.docs.json", "/cloud/v2", "")
 		if err != nil {
 			return nil, fmt.Errorf("unable to create generated data %v", err)
 		}
@@ -47,7 +48,12 @@ var testAccProtoV6ProviderFactoriesWithRoblox = map[string]func() (tfprotov6.Pro
 	},
 }
 
-func testAccPreCheck(_ *testing.T) {
+func testAccPreCheck(t *testing.T) {
+	testAccPreCheckWithServer(t, "http://localhost:8081")
+}
+
+// testAccPreCheckWithServer mocks the bookstore API at serverURL.
+func testAccPreCheckWithServer(_ *testing.T, serverURL string) {
 	httpmock.Activate()
 
 	allPublishers := make(map[string]interface{})
@@ -57,7 +63,7 @@ func testAccPreCheck(_ *testing.T) {
 	var bookCounter = 1
 
 	// Books Mock Server.
-	httpmock.RegisterResponder("POST", "=~^http://localhost:8081/publishers/\\d+/books",
+	httpmock.RegisterResponder("POST", "=~^"+serverURL+"/publishers/\\d+/books",
 		func(req *http.Request) (*http.Response, error) {
 			var requestBody map[string]interface{}
 			err := json.NewDecoder(req.Body).Decode(&requestBody)
@@ -85,7 +91,7 @@ func testAccPreCheck(_ *testing.T) {
 		},
 	)
 
-	httpmock.RegisterResponder("GET", "=~^http://localhost:8081/publishers/\\d+/books/\\d+",
+	httpmock.RegisterResponder("GET", "=~^"+serverURL+"/publishers/\\d+/books/\\d+",
 		func(req *http.Request) (*http.Response, error) {
 			// Ensure publisher has been created.
 			publisherNumber := req.URL.Path[len("/publishers/"):]
@@ -114,7 +120,7 @@ func testAccPreCheck(_ *testing.T) {
 		},
 	)
 
-	httpmock.RegisterResponder("PATCH", "=~^http://localhost:8081/publishers/\\d+/books/\\d+",
+	httpmock.RegisterResponder("PATCH", "=~^"+serverURL+"/publishers/\\d+/books/\\d+",
 		func(req *http.Request) (*http.Response, error) {
 			publisherID := strings.Split(req.URL.Path[len("/publishers/"):], "/")[0]
 			_, ok := allPublishers[publisherID]
@@ -143,7 +149,7 @@ func testAccPreCheck(_ *testing.T) {
 		},
 	)
 
-	httpmock.RegisterResponder("DELETE", "=~^http://localhost:8081/publishers/\\d+/books/\\d+",
+	httpmock.RegisterResponder("DELETE", "=~^"+serverURL+"/publishers/\\d+/books/\\d+",
 		func(req *http.Request) (*http.Response, error) {
 			publisherID := req.URL.Path[len("/publishers/"):]
 			_, ok := allPublishers[publisherID]
@@ -163,7 +169,7 @@ func testAccPreCheck(_ *testing.T) {
 		},
 	)
 
-	httpmock.RegisterResponder("POST", "http://localhost:8081/publishers",
+	httpmock.RegisterResponder("POST", serverURL+"/publishers",
 		func(req *http.Request) (*http.Response, error) {
 			var requestBody map[string]interface{}
 			err := json.NewDecoder(req.Body).Decode(&requestBody)
@@ -181,7 +187,7 @@ func testAccPreCheck(_ *testing.T) {
 		},
 	)
 
-	httpmock.RegisterResponder("GET", "=~^http://localhost:8081/publishers/\\d+",
+	httpmock.RegisterResponder("GET", "=~^"+serverURL+"/publishers/\\d+",
 		func(req *http.Request) (*http.Response, error) {
 			publisherID := req.URL.Path[len("/publishers/"):]
 			resource, ok := allPublishers[publisherID]
@@ -202,7 +208,7 @@ func testAccPreCheck(_ *testing.T) {
 		},
 	)
 
-	httpmock.RegisterResponder("PATCH", "=~^http://localhost:8081/publishers/\\d+",
+	httpmock.RegisterResponder("PATCH", "=~^"+serverURL+"/publishers/\\d+",
 		func(req *http.Request) (*http.Response, error) {
 			publisherID := req.URL.Path[len("/publishers/"):]
 			_, ok := allPublishers[publisherID]
@@ -225,7 +231,7 @@ func testAccPreCheck(_ *testing.T) {
 		},
 	)
 
-	httpmock.RegisterResponder("DELETE", "=~^http://localhost:8081/publishers/\\d+",
+	httpmock.RegisterResponder("DELETE", "=~^"+serverURL+"/publishers/\\d+",
 		func(req *http.Request) (*http.Response, error) {
 			publisherID := req.URL.Path[len("/publishers/"):]
 			_, ok := allPublishers[publisherID]
@@ -240,3 +246,33 @@ func testAccPreCheck(_ *testing.T) {
 	)
 
 }
+
+func TestProviderEndpoint(t *testing.T) {
+	t.Setenv("AEP_ENDPOINT", "")
+	tests := map[string]struct {
+		endpoint string
+		specs    int
+		want     string
+		wantErr  bool
+	}{
+		"single spec":                {endpoint: "http://localhost:9000/", specs: 1, want: "http://localhost:9000"},
+		"several specs":              {endpoint: "http://localhost:9000", specs: 2, wantErr: true},
+		"several specs, no endpoint": {specs: 2, want: ""},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := &ScaffoldingProvider{generator: &GeneratedProviderData{specs: tt.specs}}
+			model := ScaffoldingProviderModel{Endpoint: types.StringValue(tt.endpoint)}
+			if tt.endpoint == "" {
+				model.Endpoint = types.StringNull()
+			}
+			got, diags := p.endpoint(model)
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("endpoint() diagnostics = %v, want error %v", diags, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("endpoint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
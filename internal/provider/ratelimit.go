@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/time/rate"
+)
+
+// limitTransport caps the request rate and the number of requests in flight.
+//
+// A single limitTransport is shared by every resource and data source of a
+// provider instance, so the limits hold however much parallelism Terraform
+// uses.
+type limitTransport struct {
+	base http.RoundTripper
+
+	// limiter is nil if the request rate is not limited.
+	limiter *rate.Limiter
+	// slots is nil if the number of concurrent requests is not limited.
+	slots chan struct{}
+}
+
+// newLimitTransport returns a limitTransport around base, configured from the
+// requests_per_second and max_concurrent_requests provider attributes. base is
+// returned as-is if neither is set.
+func newLimitTransport(base http.RoundTripper, requestsPerSecond types.Float64, maxConcurrent types.Int64) (http.RoundTripper, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	t := &limitTransport{base: base}
+
+	if !requestsPerSecond.IsNull() {
+		rps := requestsPerSecond.ValueFloat64()
+		if rps <= 0 {
+			diags.AddAttributeError(path.Root("requests_per_second"), "Invalid requests_per_second", fmt.Sprintf("requests_per_second must be greater than 0, got %v.", rps))
+			return nil, diags
+		}
+		// Allow bursts of up to a second's worth of requests.
+		t.limiter = rate.NewLimiter(rate.Limit(rps), int(math.Max(1, math.Floor(rps))))
+	}
+	if !maxConcurrent.IsNull() {
+		n := maxConcurrent.ValueInt64()
+		if n < 1 {
+			diags.AddAttributeError(path.Root("max_concurrent_requests"), "Invalid max_concurrent_requests", fmt.Sprintf("max_concurrent_requests must be at least 1, got %d.", n))
+			return nil, diags
+		}
+		t.slots = make(chan struct{}, n)
+	}
+
+	if t.limiter == nil && t.slots == nil {
+		return base, diags
+	}
+	return t, diags
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	ctx := req.Context()
+
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		// The slot is freed once the response headers arrive rather than when
+		// the body is closed, so that a caller that never closes a body can't
+		// starve everyone else.
+		defer func() { <-t.slots }()
+	}
+
+	if t.limiter != nil {
+		if err := t.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/jarcoal/httpmock"
+)
+
+func TestLimitTransportMaxConcurrentRequests(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder("GET", "http://localhost:8081/publishers/1", func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		inFlight += 1
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight -= 1
+		mu.Unlock()
+		return httpmock.NewStringResponse(200, ""), nil
+	})
+
+	transport, diags := newLimitTransport(mock, types.Float64Null(), types.Int64Value(2))
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	c := &http.Client{Transport: transport}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.Get("http://localhost:8081/publishers/1")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Errorf("max requests in flight = %d, want at most 2", maxInFlight)
+	}
+}
+
+func TestNewLimitTransport(t *testing.T) {
+	tests := map[string]struct {
+		requestsPerSecond types.Float64
+		maxConcurrent     types.Int64
+		wantLimited       bool
+		wantErr           bool
+	}{
+		"unlimited": {
+			requestsPerSecond: types.Float64Null(),
+			maxConcurrent:     types.Int64Null(),
+		},
+		"rate limited": {
+			requestsPerSecond: types.Float64Value(0.5),
+			maxConcurrent:     types.Int64Null(),
+			wantLimited:       true,
+		},
+		"invalid rate": {
+			requestsPerSecond: types.Float64Value(0),
+			maxConcurrent:     types.Int64Null(),
+			wantErr:           true,
+		},
+		"invalid concurrency": {
+			requestsPerSecond: types.Float64Null(),
+			maxConcurrent:     types.Int64Value(0),
+			wantErr:           true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			base := httpmock.NewMockTransport()
+			got, diags := newLimitTransport(base, tt.requestsPerSecond, tt.maxConcurrent)
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("newLimitTransport() diagnostics = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			_, limited := got.(*limitTransport)
+			if limited != tt.wantLimited {
+				t.Errorf("newLimitTransport() returned %T, want limited %v", got, tt.wantLimited)
+			}
+		})
+	}
+}
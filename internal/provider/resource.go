@@ -4,7 +4,9 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/aep-dev/aep-lib-go/pkg/api"
@@ -14,12 +16,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &ExampleResource{}
 var _ resource.ResourceWithImportState = &ExampleResource{}
+var _ resource.ResourceWithUpgradeState = &ExampleResource{}
 
 func NewExampleResourceWithResource(r *api.Resource, a *api.API, n string, o *openapi.OpenAPI, res *data.ResourceSchema) func() resource.Resource {
 	return func() resource.Resource {
@@ -43,8 +46,9 @@ type ExampleResource struct {
 	api      *api.API
 	name     string
 
-	// Client will be configured at plan/apply time in the Configure() function.
+	// Client and endpoint will be configured at plan/apply time in the Configure() function.
 	client         *client.Client
+	endpoint       string
 	o              *openapi.OpenAPI
 	resourceSchema *data.ResourceSchema
 }
@@ -57,7 +61,9 @@ func (r *ExampleResource) Schema(ctx context.Context, req resource.SchemaRequest
 	attr := r.resourceSchema.FullSchema()
 
 	resp.Schema = schema.Schema{
-		MarkdownDescription: r.resource.Singular,
+		MarkdownDescription: r.resourceSchema.Description,
+		DeprecationMessage:  r.resourceSchema.DeprecationMessage,
+		Version:             r.resourceSchema.Version,
 
 		Attributes: attr,
 	}
@@ -69,18 +75,28 @@ func (r *ExampleResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.Client)
+	providerData, ok := req.ProviderData.(*ConfiguredProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *ConfiguredProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = providerData.client
+	r.endpoint = providerData.endpoint
+}
+
+// serverURL returns the URL requests are sent to.
+// The provider's endpoint takes precedence over the server URL in the spec.
+func (r *ExampleResource) serverURL() string {
+	if r.endpoint != "" {
+		return r.endpoint
+	}
+	return r.api.ServerURL
 }
 
 func (r *ExampleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -105,14 +121,15 @@ func (r *ExampleResource) Create(ctx context.Context, req resource.CreateRequest
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create body, got error: %s", err))
 		return
 	}
-	tflog.Info(ctx, fmt.Sprintf("headers %q", r.client.Headers))
 
-	a, err := r.client.Create(ctx, r.resource, r.api.ServerURL, body, parameters)
+	createCtx, responses := withResponses(ctx)
+	a, err := r.client.Create(createCtx, r.resource, r.serverURL(), body, parameters)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create example, got error: %s", err))
 		return
 	}
+	a = responses.resource(a)
 
 	dataState, err := State(ctx, a, dataPlan, r.resourceSchema)
 	if err != nil {
@@ -152,11 +169,13 @@ func (r *ExampleResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	a, err := r.client.Get(ctx, r.api.ServerURL, path)
+	getCtx, responses := withResponses(ctx)
+	a, err := r.client.Get(getCtx, r.serverURL(), path)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read example, got error: %s", err))
 		return
 	}
+	a = responses.resource(a)
 
 	dataState, err := State(ctx, a, dataResource, r.resourceSchema)
 	if err != nil {
@@ -201,19 +220,20 @@ func (r *ExampleResource) Update(ctx context.Context, req resource.UpdateRequest
 
 	}
 
-	err = r.client.Update(ctx, r.api.ServerURL, *s.String, body)
+	err = r.client.Update(ctx, r.serverURL(), *s.String, body)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update example, got error: %s", err))
 		return
 	}
 
-	a, err := r.client.Get(ctx, r.api.ServerURL, *s.String)
+	getCtx, responses := withResponses(ctx)
+	a, err := r.client.Get(getCtx, r.serverURL(), *s.String)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read example, got error: %s", err))
 		return
 	}
-	tflog.Info(ctx, fmt.Sprintf("Create response: %v", a))
+	a = responses.resource(a)
 
 	toBeState, err := State(ctx, a, dataResource, r.resourceSchema)
 	if err != nil {
@@ -248,13 +268,47 @@ func (r *ExampleResource) Delete(ctx context.Context, req resource.DeleteRequest
 
 	}
 
-	err := r.client.Delete(ctx, r.api.ServerURL, *s.String)
+	err := r.client.Delete(ctx, r.serverURL(), *s.String)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete example, got error: %s", err))
 		return
 	}
 }
 
+// UpgradeState upgrades state written with any earlier schema version. The
+// earlier schemas aren't known, so the raw state is upgraded by field number.
+func (r *ExampleResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	upgraders := make(map[int64]resource.StateUpgrader)
+	for version := int64(0); version < r.resourceSchema.Version; version++ {
+		upgraders[version] = resource.StateUpgrader{
+			StateUpgrader: r.upgradeState,
+		}
+	}
+	return upgraders
+}
+
+func (r *ExampleResource) upgradeState(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	if req.RawState == nil || req.RawState.JSON == nil {
+		resp.Diagnostics.AddError("Unable to Upgrade Resource State", "The prior state is not stored as JSON. Refresh it with an earlier version of the provider first.")
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(req.RawState.JSON))
+	decoder.UseNumber()
+	var prior map[string]interface{}
+	if err := decoder.Decode(&prior); err != nil {
+		resp.Diagnostics.AddError("Unable to Upgrade Resource State", fmt.Sprintf("Unable to parse the prior state, got error: %s", err))
+		return
+	}
+
+	upgraded, err := json.Marshal(r.resourceSchema.UpgradeState(prior))
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Upgrade Resource State", fmt.Sprintf("Unable to encode the upgraded state, got error: %s", err))
+		return
+	}
+	resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+}
+
 func (r *ExampleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("path"), req, resp)
 }
@@ -75,6 +75,32 @@ func TestBookResource(t *testing.T) {
 	})
 }
 
+func TestPublisherResourceWithEndpoint(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		PreCheck:                 func() { testAccPreCheckWithServer(t, "http://localhost:8082") },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Requests must go to the endpoint instead of the server in the spec.
+			{
+				Config: testProviderEndpointConfig("http://localhost:8082/") + testExamplePublisherConfig("pub-description"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("scaffolding_publisher.my-pub", "description", "pub-description"),
+					resource.TestCheckResourceAttr("scaffolding_publisher.my-pub", "path", "/publishers/1"),
+				),
+			},
+		},
+	})
+}
+
+func testProviderEndpointConfig(endpoint string) string {
+	return fmt.Sprintf(`
+provider "scaffolding" {
+  endpoint = %[1]q
+}
+`, endpoint)
+}
+
 func testExamplePublisherConfig(description string) string {
 	return fmt.Sprintf(`
 resource "scaffolding_publisher" "my-pub" {
@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultRetryMaxAttempts = 4
+	defaultRetryMaxWait     = 30 * time.Second
+
+	// retryBaseWait is the wait before the first retry. It doubles with every
+	// following attempt.
+	retryBaseWait = 500 * time.Millisecond
+)
+
+// retryTransport retries requests that failed with a transient error.
+//
+// Only idempotent requests are retried, plus creates that carry a request_id
+// (see AEP-155), since the server deduplicates those. Waits grow exponentially
+// with full jitter, and a Retry-After header from the server is honored.
+type retryTransport struct {
+	base http.RoundTripper
+
+	// maxAttempts is the total number of attempts, including the first.
+	maxAttempts int
+	// maxWait is the longest time waited between two attempts. If the server
+	// asks for a longer wait with Retry-After, the response is returned as-is.
+	maxWait time.Duration
+}
+
+// newRetryTransport returns a retryTransport around base, configured from the
+// retry_max_attempts and retry_max_wait provider attributes.
+func newRetryTransport(base http.RoundTripper, maxAttempts types.Int64, maxWait types.String) (*retryTransport, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	t := &retryTransport{
+		base:        base,
+		maxAttempts: defaultRetryMaxAttempts,
+		maxWait:     defaultRetryMaxWait,
+	}
+	if !maxAttempts.IsNull() {
+		if maxAttempts.ValueInt64() < 1 {
+			diags.AddAttributeError(path.Root("retry_max_attempts"), "Invalid retry_max_attempts", "retry_max_attempts must be at least 1.")
+		}
+		t.maxAttempts = int(maxAttempts.ValueInt64())
+	}
+	if !maxWait.IsNull() {
+		d, err := time.ParseDuration(maxWait.ValueString())
+		if err != nil || d < 0 {
+			diags.AddAttributeError(path.Root("retry_max_wait"), "Invalid retry_max_wait", fmt.Sprintf("retry_max_wait must be a non-negative duration such as \"30s\", got %q.", maxWait.ValueString()))
+		}
+		t.maxWait = d
+	}
+	return t, diags
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if !isRetryable(req) {
+		return base.RoundTrip(req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(req.Context())
+			r.Body = body
+		}
+
+		resp, err := base.RoundTrip(r)
+		if attempt >= t.maxAttempts || !isTransient(resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				if retryAfter > t.maxWait {
+					return resp, err
+				}
+				wait = retryAfter
+			}
+			// Drain the body so that the connection can be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		tflog.Warn(req.Context(), fmt.Sprintf("%s %s failed with %s, retrying in %s (attempt %d of %d)", req.Method, req.URL, describeFailure(resp, err), wait, attempt+1, t.maxAttempts))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the jittered wait before the attempt following attempt.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	wait := t.maxWait
+	if shift := attempt - 1; shift < 32 && retryBaseWait<<shift < t.maxWait {
+		wait = retryBaseWait << shift
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(wait) + 1))
+}
+
+// isRetryable reports whether req can be sent more than once without side
+// effects.
+func isRetryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body can't be replayed.
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return req.URL.Query().Get("request_id") != ""
+	default:
+		return false
+	}
+}
+
+// isTransient reports whether a request failed in a way that may succeed if
+// tried again.
+func isTransient(resp *http.Response, err error) bool {
+	if err != nil {
+		return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(v); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func describeFailure(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
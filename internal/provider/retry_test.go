@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+)
+
+func TestRetryTransport(t *testing.T) {
+	tests := map[string]struct {
+		method     string
+		url        string
+		responses  []*http.Response
+		wantStatus int
+		wantCalls  int
+	}{
+		"get retried until success": {
+			method:     "GET",
+			url:        "http://localhost:8081/publishers/1",
+			responses:  []*http.Response{httpmock.NewStringResponse(503, ""), httpmock.NewStringResponse(502, ""), httpmock.NewStringResponse(200, "")},
+			wantStatus: 200,
+			wantCalls:  3,
+		},
+		"gives up after max attempts": {
+			method:     "DELETE",
+			url:        "http://localhost:8081/publishers/1",
+			responses:  []*http.Response{httpmock.NewStringResponse(429, ""), httpmock.NewStringResponse(429, ""), httpmock.NewStringResponse(429, ""), httpmock.NewStringResponse(200, "")},
+			wantStatus: 429,
+			wantCalls:  3,
+		},
+		"permanent errors not retried": {
+			method:     "GET",
+			url:        "http://localhost:8081/publishers/1",
+			responses:  []*http.Response{httpmock.NewStringResponse(500, ""), httpmock.NewStringResponse(200, "")},
+			wantStatus: 500,
+			wantCalls:  1,
+		},
+		"create without request id not retried": {
+			method:     "POST",
+			url:        "http://localhost:8081/publishers",
+			responses:  []*http.Response{httpmock.NewStringResponse(503, ""), httpmock.NewStringResponse(200, "")},
+			wantStatus: 503,
+			wantCalls:  1,
+		},
+		"create with request id retried": {
+			method:     "POST",
+			url:        "http://localhost:8081/publishers?request_id=abc",
+			responses:  []*http.Response{httpmock.NewStringResponse(503, ""), httpmock.NewStringResponse(200, "")},
+			wantStatus: 200,
+			wantCalls:  2,
+		},
+		"update not retried": {
+			method:     "PATCH",
+			url:        "http://localhost:8081/publishers/1",
+			responses:  []*http.Response{httpmock.NewStringResponse(503, ""), httpmock.NewStringResponse(200, "")},
+			wantStatus: 503,
+			wantCalls:  1,
+		},
+		"retry-after honored": {
+			method:     "GET",
+			url:        "http://localhost:8081/publishers/1",
+			responses:  []*http.Response{withHeader(httpmock.NewStringResponse(429, ""), "Retry-After", "0"), httpmock.NewStringResponse(200, "")},
+			wantStatus: 200,
+			wantCalls:  2,
+		},
+		"retry-after longer than max wait": {
+			method:     "GET",
+			url:        "http://localhost:8081/publishers/1",
+			responses:  []*http.Response{withHeader(httpmock.NewStringResponse(429, ""), "Retry-After", "120"), httpmock.NewStringResponse(200, "")},
+			wantStatus: 429,
+			wantCalls:  1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mock := httpmock.NewMockTransport()
+			calls := 0
+			mock.RegisterResponder(tt.method, tt.url, func(req *http.Request) (*http.Response, error) {
+				resp := tt.responses[calls]
+				calls += 1
+				return resp, nil
+			})
+
+			c := &http.Client{Transport: &retryTransport{
+				base:        mock,
+				maxAttempts: 3,
+				maxWait:     time.Millisecond,
+			}}
+			req, err := http.NewRequest(tt.method, tt.url, strings.NewReader(`{"description": "my-pub"}`))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func withHeader(resp *http.Response, key string, value string) *http.Response {
+	resp.Header.Set(key, value)
+	return resp
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// tlsSettings holds the TLS configuration for requests to the API and for
+// fetching the OpenAPI spec.
+type tlsSettings struct {
+	caCertPEM          string
+	caCertFile         string
+	clientCert         string
+	clientKey          string
+	insecureSkipVerify bool
+}
+
+// tlsSettingsFromEnv reads the TLS settings from the environment.
+//
+// The spec is fetched when the plugin starts, before the provider block is
+// read, so the environment is the only way to configure TLS for that fetch.
+func tlsSettingsFromEnv() (tlsSettings, error) {
+	s := tlsSettings{
+		caCertPEM:  os.Getenv("AEP_CA_CERT_PEM"),
+		caCertFile: os.Getenv("AEP_CA_CERT_FILE"),
+		clientCert: os.Getenv("AEP_CLIENT_CERT"),
+		clientKey:  os.Getenv("AEP_CLIENT_KEY"),
+	}
+	if v := os.Getenv("AEP_INSECURE_SKIP_VERIFY"); v != "" {
+		insecure, err := strconv.ParseBool(v)
+		if err != nil {
+			return tlsSettings{}, fmt.Errorf("AEP_INSECURE_SKIP_VERIFY must be a boolean, got %q", v)
+		}
+		s.insecureSkipVerify = insecure
+	}
+	return s, nil
+}
+
+// merge overrides the settings with any attribute set in the provider block.
+func (s tlsSettings) merge(m ScaffoldingProviderModel) tlsSettings {
+	setString(&s.caCertPEM, m.CACertPEM)
+	setString(&s.caCertFile, m.CACertFile)
+	setString(&s.clientCert, m.ClientCert)
+	setString(&s.clientKey, m.ClientKey)
+	if !m.InsecureSkipVerify.IsNull() {
+		s.insecureSkipVerify = m.InsecureSkipVerify.ValueBool()
+	}
+	return s
+}
+
+func (s tlsSettings) isSet() bool {
+	return s != tlsSettings{}
+}
+
+// config returns the tls.Config for the settings. CA certificates are trusted
+// in addition to the system roots.
+func (s tlsSettings) config() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// This is only ever enabled explicitly, for development.
+		InsecureSkipVerify: s.insecureSkipVerify, // #nosec G402
+	}
+
+	if s.caCertPEM != "" || s.caCertFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if s.caCertPEM != "" && !pool.AppendCertsFromPEM([]byte(s.caCertPEM)) {
+			return nil, fmt.Errorf("ca_cert_pem does not contain a valid PEM certificate")
+		}
+		if s.caCertFile != "" {
+			pem, err := os.ReadFile(s.caCertFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read ca_cert_file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("ca_cert_file %s does not contain a valid PEM certificate", s.caCertFile)
+			}
+		}
+		cfg.RootCAs = pool
+	}
+
+	if s.clientCert != "" || s.clientKey != "" {
+		if s.clientCert == "" || s.clientKey == "" {
+			return nil, fmt.Errorf("client_cert and client_key must be set together")
+		}
+		cert, err := tls.X509KeyPair([]byte(s.clientCert), []byte(s.clientKey))
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// transport returns a transport that uses the settings, or nil (meaning
+// http.DefaultTransport) if none are set.
+func (s tlsSettings) transport() (http.RoundTripper, error) {
+	if !s.isSet() {
+		return nil, nil
+	}
+	cfg, err := s.config()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if d, ok := http.DefaultTransport.(*http.Transport); ok {
+		t = d.Clone()
+	}
+	t.TLSClientConfig = cfg
+	return t, nil
+}
+
+func setString(dst *string, v types.String) {
+	if !v.IsNull() && !v.IsUnknown() {
+		*dst = v.ValueString()
+	}
+}
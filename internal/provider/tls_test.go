@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTLSSettingsConfig(t *testing.T) {
+	certPEM, keyPEM := testCertificate(t)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(certPEM), 0o600); err != nil {
+		t.Fatalf("unable to write CA file: %v", err)
+	}
+
+	tests := map[string]struct {
+		settings        tlsSettings
+		wantRootCAs     bool
+		wantClientCerts int
+		wantErr         bool
+	}{
+		"ca pem": {
+			settings:    tlsSettings{caCertPEM: certPEM},
+			wantRootCAs: true,
+		},
+		"ca file": {
+			settings:    tlsSettings{caCertFile: caFile},
+			wantRootCAs: true,
+		},
+		"invalid ca pem": {
+			settings: tlsSettings{caCertPEM: "not a certificate"},
+			wantErr:  true,
+		},
+		"missing ca file": {
+			settings: tlsSettings{caCertFile: filepath.Join(t.TempDir(), "missing.pem")},
+			wantErr:  true,
+		},
+		"client certificate": {
+			settings:        tlsSettings{clientCert: certPEM, clientKey: keyPEM},
+			wantClientCerts: 1,
+		},
+		"client certificate without key": {
+			settings: tlsSettings{clientCert: certPEM},
+			wantErr:  true,
+		},
+		"insecure": {
+			settings: tlsSettings{insecureSkipVerify: true},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := tt.settings.config()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("config() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (cfg.RootCAs != nil) != tt.wantRootCAs {
+				t.Errorf("config() RootCAs set = %v, want %v", cfg.RootCAs != nil, tt.wantRootCAs)
+			}
+			if len(cfg.Certificates) != tt.wantClientCerts {
+				t.Errorf("config() has %d client certificates, want %d", len(cfg.Certificates), tt.wantClientCerts)
+			}
+			if cfg.InsecureSkipVerify != tt.settings.insecureSkipVerify {
+				t.Errorf("config() InsecureSkipVerify = %v, want %v", cfg.InsecureSkipVerify, tt.settings.insecureSkipVerify)
+			}
+		})
+	}
+}
+
+// testCertificate returns a self-signed certificate and its key, PEM-encoded.
+func testCertificate(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unable to marshal key: %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"flag"
-	"fmt"
 	"log"
 	"net/http"
 
@@ -11,7 +10,6 @@ import (
 	"github.com/aep-dev/terraform-provider-aep/config"
 	"github.com/aep-dev/terraform-provider-aep/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var (
@@ -25,14 +23,9 @@ func main() {
 	flag.Parse()
 
 	cfg := config.NewProviderConfig()
+	// Requests and responses are logged by the provider itself, with secrets redacted.
 	c := client.NewClient(http.DefaultClient)
 
-	c.RequestLoggingFunction = func(ctx context.Context, req *http.Request, args ...any) {
-		tflog.Info(ctx, fmt.Sprintf("Sending %s request to %s", req.Method, req.URL))
-	}
-
-	c.ResponseLoggingFunction = func(ctx context.Context, resp *http.Response, args ...any) {}
-
 	p, err := provider.NewProvider(&cfg, c, version)
 	if err != nil {
 		log.Fatal(err.Error())
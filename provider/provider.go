@@ -19,7 +19,15 @@ type Provider struct {
 }
 
 func NewProvider(config *config.ProviderConfig, client *client.Client, version string) (*Provider, error) {
-	gen, err := internalprovider.CreateGeneratedProviderData(context.Background(), config.OpenAPIPath(), config.PathPrefix())
+	specs, err := config.OpenAPISpecs()
+	if err != nil {
+		return nil, err
+	}
+	options, err := config.GeneratorOptions()
+	if err != nil {
+		return nil, err
+	}
+	gen, err := internalprovider.CreateGeneratedProviderDataFromSpecs(context.Background(), specs, options)
 	if err != nil {
 		return nil, err
 	}